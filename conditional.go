@@ -0,0 +1,133 @@
+package medium
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StrongETag returns a strong ETag for the given version, e.g. a database
+// revision or content hash. Strong ETags indicate the response is byte for
+// byte identical to other responses with the same ETag.
+func StrongETag(version string) string { return `"` + version + `"` }
+
+// WeakETag returns a weak ETag for the given version. Weak ETags indicate the
+// response is semantically equivalent to other responses with the same ETag,
+// e.g. a page rendered from the same record.
+func WeakETag(version string) string { return `W/"` + version + `"` }
+
+// BodyETag returns an ETag computed from the hash of body.
+func BodyETag(body []byte, weak bool) string {
+	hash := sha256.Sum256(body)
+	version := hex.EncodeToString(hash[:16])
+
+	if weak {
+		return WeakETag(version)
+	}
+
+	return StrongETag(version)
+}
+
+// CheckPreconditions evaluates the request's conditional headers against the
+// current ETag and modification time of the resource, either of which may be
+// empty. A nil Response is returned if the request should be handled
+// normally.
+//
+// For GET and HEAD requests a 304 Not Modified response is returned when the
+// client's copy is current according to If-None-Match or If-Modified-Since.
+// For other methods a 412 Precondition Failed response is returned when
+// If-Match or If-Unmodified-Since don't match the current resource, which
+// prevents lost updates when used before modifying a resource in PUT or
+// PATCH handlers.
+func CheckPreconditions(r requestable, etag string, lastModified time.Time) Response {
+	req := r.Request()
+	header := req.Header
+	safe := req.Method == http.MethodGet || req.Method == http.MethodHead
+
+	if ifMatch := header.Get("If-Match"); ifMatch != "" {
+		if !etagMatches(ifMatch, etag, false) {
+			return preconditionFailed()
+		}
+	} else if since, ok := parseHTTPTime(header.Get("If-Unmodified-Since")); ok && !lastModified.IsZero() {
+		if lastModified.Truncate(time.Second).After(since) {
+			return preconditionFailed()
+		}
+	}
+
+	if ifNoneMatch := header.Get("If-None-Match"); ifNoneMatch != "" {
+		if !etagMatches(ifNoneMatch, etag, true) {
+			return nil
+		}
+
+		if safe {
+			return notModified(etag, lastModified)
+		}
+
+		return preconditionFailed()
+	}
+
+	if since, ok := parseHTTPTime(header.Get("If-Modified-Since")); ok && safe && !lastModified.IsZero() {
+		if !lastModified.Truncate(time.Second).After(since) {
+			return notModified(etag, lastModified)
+		}
+	}
+
+	return nil
+}
+
+// etagMatches reports whether etag matches any of the ETags in the header
+// value. Weak comparison ignores the weak indicator, while strong comparison
+// requires both ETags to be strong.
+func etagMatches(value string, etag string, weak bool) bool {
+	if strings.TrimSpace(value) == "*" {
+		return etag != ""
+	}
+
+	if etag == "" || (!weak && strings.HasPrefix(etag, "W/")) {
+		return false
+	}
+
+	for _, candidate := range strings.Split(value, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if !weak && strings.HasPrefix(candidate, "W/") {
+			continue
+		}
+
+		if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseHTTPTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	parsed, err := http.ParseTime(value)
+
+	return parsed, err == nil
+}
+
+func notModified(etag string, lastModified time.Time) Response {
+	res := NewResponse()
+	res.WriteStatus(http.StatusNotModified)
+
+	if etag != "" {
+		res.Header().Set("ETag", etag)
+	}
+
+	if !lastModified.IsZero() {
+		res.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	return res
+}
+
+func preconditionFailed() Response {
+	return StringResponse(http.StatusPreconditionFailed, "412 precondition failed")
+}
@@ -0,0 +1,107 @@
+package medium
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestETags(t *testing.T) {
+	require.Equal(t, `"v1"`, StrongETag("v1"))
+	require.Equal(t, `W/"v1"`, WeakETag("v1"))
+	require.Equal(t, `"2cf24dba5fb0a30e26e83b2ac5b9e29e"`, BodyETag([]byte("hello"), false))
+	require.Equal(t, `W/"2cf24dba5fb0a30e26e83b2ac5b9e29e"`, BodyETag([]byte("hello"), true))
+}
+
+func TestCheckPreconditions(t *testing.T) {
+	modified := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	before := modified.Add(-time.Hour).Format(http.TimeFormat)
+	after := modified.Add(time.Hour).Format(http.TimeFormat)
+
+	testCases := map[string]struct {
+		method string
+		header map[string]string
+		etag   string
+		status int
+	}{
+		"no conditions":                {method: http.MethodGet, etag: `"v1"`, status: 0},
+		"if-none-match matches":        {method: http.MethodGet, header: map[string]string{"If-None-Match": `"v0", "v1"`}, etag: `"v1"`, status: http.StatusNotModified},
+		"if-none-match weak":           {method: http.MethodGet, header: map[string]string{"If-None-Match": `W/"v1"`}, etag: `"v1"`, status: http.StatusNotModified},
+		"if-none-match differs":        {method: http.MethodGet, header: map[string]string{"If-None-Match": `"v0"`}, etag: `"v1"`, status: 0},
+		"if-none-match star":           {method: http.MethodHead, header: map[string]string{"If-None-Match": `*`}, etag: `"v1"`, status: http.StatusNotModified},
+		"if-none-match wins":           {method: http.MethodGet, header: map[string]string{"If-None-Match": `"v0"`, "If-Modified-Since": after}, etag: `"v1"`, status: 0},
+		"if-modified-since current":    {method: http.MethodGet, header: map[string]string{"If-Modified-Since": after}, status: http.StatusNotModified},
+		"if-modified-since stale":      {method: http.MethodGet, header: map[string]string{"If-Modified-Since": before}, status: 0},
+		"if-modified-since on put":     {method: http.MethodPut, header: map[string]string{"If-Modified-Since": after}, status: 0},
+		"if-match matches":             {method: http.MethodPut, header: map[string]string{"If-Match": `"v1"`}, etag: `"v1"`, status: 0},
+		"if-match differs":             {method: http.MethodPatch, header: map[string]string{"If-Match": `"v0"`}, etag: `"v1"`, status: http.StatusPreconditionFailed},
+		"if-match requires strong":     {method: http.MethodPut, header: map[string]string{"If-Match": `W/"v1"`}, etag: `W/"v1"`, status: http.StatusPreconditionFailed},
+		"if-match star":                {method: http.MethodPut, header: map[string]string{"If-Match": `*`}, etag: `"v1"`, status: 0},
+		"if-match star missing":        {method: http.MethodPut, header: map[string]string{"If-Match": `*`}, status: http.StatusPreconditionFailed},
+		"if-unmodified-since current":  {method: http.MethodPut, header: map[string]string{"If-Unmodified-Since": after}, status: 0},
+		"if-unmodified-since modified": {method: http.MethodPatch, header: map[string]string{"If-Unmodified-Since": before}, status: http.StatusPreconditionFailed},
+		"if-none-match on put":         {method: http.MethodPut, header: map[string]string{"If-None-Match": `*`}, etag: `"v1"`, status: http.StatusPreconditionFailed},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/", nil)
+			for key, value := range tc.header {
+				req.Header.Set(key, value)
+			}
+
+			res := CheckPreconditions(NewRequest(req, NoData{}, nil), tc.etag, modified)
+
+			if tc.status == 0 {
+				require.Nil(t, res)
+				return
+			}
+
+			require.NotNil(t, res)
+			require.Equal(t, tc.status, res.Status())
+
+			if tc.status == http.StatusNotModified {
+				require.Equal(t, tc.etag, res.Header().Get("ETag"))
+				require.Equal(t, modified.Format(http.TimeFormat), res.Header().Get("Last-Modified"))
+				require.Nil(t, res.Body())
+			}
+		})
+	}
+}
+
+func TestCheckPreconditions_Handler(t *testing.T) {
+	version := "3"
+
+	router := New(WithNoData)
+	router.Put("/posts/1", func(ctx context.Context, r *Request[NoData]) Response {
+		if res := CheckPreconditions(r, StrongETag(version), time.Time{}); res != nil {
+			return res
+		}
+
+		version = "4"
+		res := StringResponse(http.StatusOK, "updated")
+		res.Header().Set("ETag", StrongETag(version))
+
+		return res
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/posts/1", nil)
+	req.Header.Set("If-Match", `"2"`)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusPreconditionFailed, rw.Code)
+	require.Equal(t, "3", version)
+
+	req = httptest.NewRequest(http.MethodPut, "/posts/1", nil)
+	req.Header.Set("If-Match", `"3"`)
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, `"4"`, rw.Header().Get("ETag"))
+}
@@ -1,30 +1,40 @@
 /*
-The router package provides a simple interface that maps routes to handlers. The router accepts a type argument which must implement the Action interface. This allows clients to define extend and enhance the BaseAction type with additional data and functionality.
+The medium package provides a simple interface that maps routes to handlers. The router accepts a type argument for the application specific data that is created for each request and passed to BeforeFuncs, handlers, and AfterFuncs via Request.Data.
+
+For each group a request passes through, AroundFuncs are called first, followed by BeforeFuncs, the handler, and finally AfterFuncs, which can replace the handler's Response.
 
 Example:
 
 	package main
 
-	type MyAction struct {
-		requestId string
-		*router.BaseAction
+	type ReqData struct {
+		requestID string
 	}
 
 	func Run() {
-		// Router that defines a context creator that returns a new MyAction for each request.
-		router := New(func(ac *MyAction) {
-			ac.requestId = randomString()
-		}))
-
-		// Add an Around handler that sets the requestId header
-		router.Around(func(ac *MyAction, next func()) {
-			ac.Response.Header().Add("X-Request-ID", ac.requestId)
-			next()
+		// Router that defines a data creator that returns a new ReqData for each request.
+		router := medium.New(func(r *medium.RootRequest) ReqData {
+			return ReqData{requestID: randomString()}
+		})
+
+		// Add an Around handler that logs how long each request takes
+		router.Around(func(ctx context.Context, req *medium.Request[ReqData], next medium.Next) medium.Response {
+			start := time.Now()
+			res := next(ctx)
+			mlog.Info(ctx, "request served", mlog.Fields{"status": res.Status(), "duration": time.Since(start)})
+
+			return res
+		})
+
+		// Add an After handler that sets the requestID header
+		router.After(func(ctx context.Context, req *medium.Request[ReqData], res medium.Response) medium.Response {
+			res.Header().Set("X-Request-ID", req.Data.requestID)
+			return res
 		})
 
-		// Echo back the requestId header
-		router.Get("/echo", func(ac *MyAction) {
-			ac.Write([]byte(ac.requestId))
+		// Echo back the requestID
+		router.Get("/echo", func(ctx context.Context, req *medium.Request[ReqData]) medium.Response {
+			return medium.StringResponse(http.StatusOK, req.Data.requestID)
 		})
 
 		http.ListenAndServe(":8080", router)
@@ -0,0 +1,178 @@
+package medium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/blakewilliams/medium/mlog"
+)
+
+// HandlerFuncWithError is a handler that can return an error instead of
+// building an error response by hand. Use HandleErrors to register it as a
+// route.
+type HandlerFuncWithError[T any] func(context.Context, *Request[T]) (Response, error)
+
+// ErrorHandler maps errors returned by handlers to a Response. See
+// RouteGroup.ErrorHandler for more information.
+type ErrorHandler[T any] func(ctx context.Context, req *Request[T], err error) Response
+
+// HandleErrors converts a handler that returns an error into a HandlerFunc so
+// that it can be registered as a route. Returned errors are passed to the
+// ErrorHandler of the route's group, or the closest parent that has one.
+//
+//	router.Get("/users/:id", medium.HandleErrors(func(ctx context.Context, req *medium.Request[ReqData]) (medium.Response, error) {
+//		user, err := findUser(req.Param("id"))
+//		if err != nil {
+//			return nil, err
+//		}
+//
+//		return medium.JSON(http.StatusOK, user), nil
+//	}))
+func HandleErrors[T any](handler HandlerFuncWithError[T]) HandlerFunc[T] {
+	return func(ctx context.Context, req *Request[T]) Response {
+		res, err := handler(ctx, req)
+		if err != nil {
+			return ErrorResponse(err)
+		}
+
+		return res
+	}
+}
+
+// ErrorResponse returns a Response that passes err to the ErrorHandler of the
+// route's group, or the closest parent that has one. This allows BeforeFuncs
+// and other HandlerFuncs to return errors the same way as handlers wrapped
+// with HandleErrors.
+//
+// If no group handles the error, the response has the status returned by
+// ErrorStatus and a body describing the error. Details of server errors are
+// omitted from the body.
+func ErrorResponse(err error) Response {
+	status := ErrorStatus(err)
+	message := strings.ToLower(http.StatusText(status))
+
+	var httpErr *HTTPError
+	var bindErr *BindError
+
+	switch {
+	case status >= 500:
+	case errors.As(err, &httpErr) && httpErr.Message != "":
+		message = httpErr.Message
+	case errors.As(err, &bindErr):
+		message = bindErr.Error()
+	}
+
+	return &errorResponse{
+		Response: StringResponse(status, fmt.Sprintf("%d %s", status, message)),
+		err:      err,
+	}
+}
+
+// errorResponse is returned by ErrorResponse and holds the error until it's
+// handled by an ErrorHandler.
+type errorResponse struct {
+	Response
+	err error
+}
+
+// HTTPError is an error with an HTTP status code. HTTPErrors returned by
+// handlers are responded to with their status and message. It can be
+// returned to the client directly via JSON, e.g. `JSON(err.Status, err)`.
+type HTTPError struct {
+	// Status is the HTTP status code of the error.
+	Status int `json:"-"`
+	// Message describes the error and is safe to show to clients.
+	Message string `json:"message"`
+	// Fields maps the names of invalid fields to a description of the
+	// problem. See Validation.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Err is the underlying error, if any.
+	Err error `json:"-"`
+}
+
+// NewHTTPError returns an HTTPError with the given status and message.
+func NewHTTPError(status int, message string) *HTTPError {
+	return &HTTPError{Status: status, Message: message}
+}
+
+// BadRequest returns an HTTPError with a 400 Bad Request status.
+func BadRequest(message string) *HTTPError {
+	return NewHTTPError(http.StatusBadRequest, message)
+}
+
+// Unauthorized returns an HTTPError with a 401 Unauthorized status.
+func Unauthorized(message string) *HTTPError {
+	return NewHTTPError(http.StatusUnauthorized, message)
+}
+
+// Forbidden returns an HTTPError with a 403 Forbidden status.
+func Forbidden(message string) *HTTPError {
+	return NewHTTPError(http.StatusForbidden, message)
+}
+
+// NotFound returns an HTTPError with a 404 Not Found status.
+func NotFound(message string) *HTTPError {
+	return NewHTTPError(http.StatusNotFound, message)
+}
+
+// Conflict returns an HTTPError with a 409 Conflict status.
+func Conflict(message string) *HTTPError {
+	return NewHTTPError(http.StatusConflict, message)
+}
+
+// Validation returns an HTTPError with a 422 Unprocessable Entity status
+// describing the invalid fields.
+func Validation(fields map[string]string) *HTTPError {
+	return &HTTPError{Status: http.StatusUnprocessableEntity, Message: "validation failed", Fields: fields}
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *HTTPError) Unwrap() error { return e.Err }
+
+// Wrap sets the underlying error and returns the HTTPError, e.g.
+// `NotFound("user not found").Wrap(err)`.
+func (e *HTTPError) Wrap(err error) *HTTPError {
+	e.Err = err
+
+	return e
+}
+
+// ErrorStatus returns the HTTP status code that best describes err. The
+// status of HTTPErrors and BindErrors is used, and other errors result in a
+// 500 Internal Server Error.
+func ErrorStatus(err error) int {
+	var httpErr *HTTPError
+	var bindErr *BindError
+
+	switch {
+	case errors.As(err, &httpErr):
+		return httpErr.Status
+	case errors.As(err, &bindErr):
+		return bindErr.Status
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// logHandlerError logs an error returned by a handler. Server errors are
+// logged as errors and client errors as info, since they're expected.
+func logHandlerError(ctx context.Context, err error) {
+	status := ErrorStatus(err)
+	fields := mlog.Fields{"error": err.Error(), "status": status}
+
+	if status >= 500 {
+		mlog.Error(ctx, "handler returned error", fields)
+	} else {
+		mlog.Info(ctx, "handler returned error", fields)
+	}
+}
@@ -0,0 +1,138 @@
+package medium
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blakewilliams/medium/mlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleErrors_DefaultResponses(t *testing.T) {
+	testCases := map[string]struct {
+		err    error
+		status int
+		body   string
+	}{
+		"not found":          {err: NotFound("user not found"), status: http.StatusNotFound, body: "404 user not found"},
+		"forbidden":          {err: Forbidden(""), status: http.StatusForbidden, body: "403 forbidden"},
+		"validation":         {err: Validation(map[string]string{"name": "is required"}), status: http.StatusUnprocessableEntity, body: "422 validation failed"},
+		"wrapped http error": {err: fmt.Errorf("loading user: %w", Conflict("already exists")), status: http.StatusConflict, body: "409 already exists"},
+		"bind error":         {err: &BindError{Status: http.StatusBadRequest, Field: "name", Message: "expected string"}, status: http.StatusBadRequest, body: "400 name: expected string"},
+		"unknown error":      {err: errors.New("database is on fire"), status: http.StatusInternalServerError, body: "500 internal server error"},
+		"server http error":  {err: NewHTTPError(http.StatusBadGateway, "upstream secret"), status: http.StatusBadGateway, body: "502 bad gateway"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			router := New(WithNoData)
+			router.Get("/", HandleErrors(func(ctx context.Context, r *Request[NoData]) (Response, error) {
+				return nil, tc.err
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rw := httptest.NewRecorder()
+			router.ServeHTTP(rw, req)
+
+			require.Equal(t, tc.status, rw.Code)
+			require.Equal(t, tc.body, rw.Body.String())
+		})
+	}
+}
+
+func TestHandleErrors_ErrorHandler(t *testing.T) {
+	var logs bytes.Buffer
+	logger := mlog.New(&logs, mlog.LevelDebug, mlog.JSONFormatter{})
+
+	router := New(func(r *RootRequest) string { return "root" })
+	router.Use(func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		next(rw, r.WithContext(mlog.Inject(r.Context(), logger)))
+	})
+	router.ErrorHandler(func(ctx context.Context, r *Request[string], err error) Response {
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			return JSON(httpErr.Status, httpErr)
+		}
+
+		return StringResponse(ErrorStatus(err), r.Data+" handled: "+err.Error())
+	})
+
+	group := Group(router, func(r *Request[string]) MyData { return MyData{Value: 1} })
+	group.Get("/validation", HandleErrors(func(ctx context.Context, r *Request[MyData]) (Response, error) {
+		return nil, Validation(map[string]string{"name": "is required"})
+	}))
+	group.Get("/ok", HandleErrors(func(ctx context.Context, r *Request[MyData]) (Response, error) {
+		return OK(), nil
+	}))
+
+	admin := Group(group, func(r *Request[MyData]) bool { return false })
+	admin.ErrorHandler(func(ctx context.Context, r *Request[bool], err error) Response {
+		return StringResponse(ErrorStatus(err), "admin handled")
+	})
+	admin.Before(func(ctx context.Context, r *Request[bool], next Next) Response {
+		if !r.Data {
+			return ErrorResponse(Forbidden("admins only"))
+		}
+
+		return next(ctx)
+	})
+	admin.Get("/admin", func(ctx context.Context, r *Request[bool]) Response {
+		return OK()
+	})
+
+	router.Get("/boom", HandleErrors(func(ctx context.Context, r *Request[string]) (Response, error) {
+		return nil, errors.New("boom")
+	}))
+
+	testCases := map[string]struct {
+		path   string
+		status int
+		body   string
+	}{
+		"group error handled by router": {path: "/validation", status: http.StatusUnprocessableEntity, body: `{"message":"validation failed","fields":{"name":"is required"}}` + "\n"},
+		"no error":                      {path: "/ok", status: http.StatusOK, body: "OK"},
+		"handled by closest group":      {path: "/admin", status: http.StatusForbidden, body: "admin handled"},
+		"root error":                    {path: "/boom", status: http.StatusInternalServerError, body: "root handled: boom"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			rw := httptest.NewRecorder()
+			router.ServeHTTP(rw, req)
+
+			require.Equal(t, tc.status, rw.Code)
+			require.Equal(t, tc.body, rw.Body.String())
+		})
+	}
+
+	require.Contains(t, logs.String(), `"error":"boom"`)
+	require.Contains(t, logs.String(), `"level":"error"`)
+	require.Contains(t, logs.String(), `"error":"admins only"`)
+}
+
+func TestHandleErrors_AfterSeesHandledResponse(t *testing.T) {
+	router := New(WithNoData)
+	router.ErrorHandler(func(ctx context.Context, r *Request[NoData], err error) Response {
+		return StringResponse(http.StatusTeapot, "handled")
+	})
+	router.After(func(ctx context.Context, r *Request[NoData], res Response) Response {
+		res.Header().Set("X-Status", fmt.Sprint(res.Status()))
+		return res
+	})
+	router.Get("/", HandleErrors(func(ctx context.Context, r *Request[NoData]) (Response, error) {
+		return nil, errors.New("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusTeapot, rw.Code)
+	require.Equal(t, "418", rw.Header().Get("X-Status"))
+}
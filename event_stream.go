@@ -0,0 +1,170 @@
+package medium
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHeartbeatInterval is how often EventStream sends a heartbeat comment
+// to keep idle connections from being closed by proxies.
+const DefaultHeartbeatInterval = 15 * time.Second
+
+// Event is a single Server-Sent Event.
+type Event struct {
+	// ID sets the event ID, which the client sends back via the Last-Event-ID
+	// header when reconnecting.
+	ID string
+	// Event is the event type. Clients default to "message" if empty.
+	Event string
+	// Data is the event payload. Multi-line data is sent as multiple data
+	// fields.
+	Data string
+	// Retry tells the client how long to wait before reconnecting.
+	Retry time.Duration
+}
+
+// EventStreamConfig configures an event stream created by
+// EventStreamWithConfig.
+type EventStreamConfig struct {
+	// HeartbeatInterval is how often a heartbeat comment is sent. Defaults to
+	// DefaultHeartbeatInterval. A negative value disables heartbeats.
+	HeartbeatInterval time.Duration
+}
+
+// EventStreamFunc sends events to the client until it returns or the client
+// disconnects, which cancels ctx.
+type EventStreamFunc func(ctx context.Context, events *EventWriter) error
+
+// EventStream returns a response that sends Server-Sent Events written by fn.
+// The connection is kept open until fn returns or the client disconnects, and
+// heartbeats are sent periodically to keep the connection alive.
+//
+// r is used to read the Last-Event-ID header sent by reconnecting clients,
+// which is available via EventWriter.LastEventID.
+func EventStream(ctx context.Context, r requestable, fn EventStreamFunc) *StreamResponse {
+	return EventStreamWithConfig(ctx, r, EventStreamConfig{}, fn)
+}
+
+// EventStreamWithConfig has the same behavior as EventStream but accepts an
+// EventStreamConfig.
+func EventStreamWithConfig(ctx context.Context, r requestable, config EventStreamConfig, fn EventStreamFunc) *StreamResponse {
+	if config.HeartbeatInterval == 0 {
+		config.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+
+	lastEventID := r.Request().Header.Get("Last-Event-ID")
+
+	res := Stream(ctx, http.StatusOK, func(ctx context.Context, w *StreamWriter) error {
+		events := &EventWriter{w: w, lastEventID: lastEventID}
+
+		if config.HeartbeatInterval > 0 {
+			done := make(chan struct{})
+			var wg sync.WaitGroup
+
+			// Wait for the heartbeat to stop so it can't write after the
+			// response has been written.
+			defer func() {
+				close(done)
+				wg.Wait()
+			}()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				events.heartbeat(ctx, done, config.HeartbeatInterval)
+			}()
+		}
+
+		return fn(ctx, events)
+	})
+
+	res.Header().Set("Content-Type", "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("X-Accel-Buffering", "no")
+
+	return res
+}
+
+// EventWriter sends events to the client of an event stream. It is safe for
+// concurrent use.
+type EventWriter struct {
+	mu          sync.Mutex
+	w           *StreamWriter
+	lastEventID string
+}
+
+// LastEventID returns the Last-Event-ID header sent by the client when
+// reconnecting, allowing missed events to be resent.
+func (ew *EventWriter) LastEventID() string { return ew.lastEventID }
+
+// Send writes the event to the client and flushes it.
+func (ew *EventWriter) Send(event Event) error {
+	var b strings.Builder
+
+	if event.ID != "" {
+		b.WriteString("id: " + sanitizeEventField(event.ID) + "\n")
+	}
+
+	if event.Event != "" {
+		b.WriteString("event: " + sanitizeEventField(event.Event) + "\n")
+	}
+
+	if event.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(event.Retry.Milliseconds(), 10) + "\n")
+	}
+
+	data := strings.ReplaceAll(event.Data, "\r\n", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + strings.ReplaceAll(line, "\r", "") + "\n")
+	}
+
+	b.WriteString("\n")
+
+	return ew.write(b.String())
+}
+
+// Comment writes a comment to the client, which is ignored by EventSource
+// clients but keeps the connection active.
+func (ew *EventWriter) Comment(text string) error {
+	return ew.write(": " + sanitizeEventField(text) + "\n\n")
+}
+
+func (ew *EventWriter) write(s string) error {
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+
+	if _, err := ew.w.WriteString(s); err != nil {
+		return err
+	}
+
+	return ew.w.Flush()
+}
+
+// heartbeat sends a comment every interval until done is closed or ctx is
+// canceled.
+func (ew *EventWriter) heartbeat(ctx context.Context, done <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := ew.Comment("heartbeat"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sanitizeEventField removes newlines, which would otherwise end the field.
+func sanitizeEventField(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
@@ -0,0 +1,166 @@
+package medium
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStream(t *testing.T) {
+	router := New(WithNoData)
+	router.Get("/events", func(ctx context.Context, r *Request[NoData]) Response {
+		return EventStream(ctx, r, func(ctx context.Context, events *EventWriter) error {
+			if err := events.Send(Event{ID: events.LastEventID() + "1", Event: "update", Data: "line one\nline two", Retry: 3 * time.Second}); err != nil {
+				return err
+			}
+
+			return events.Send(Event{Data: "plain"})
+		})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Last-Event-ID", "4")
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.True(t, rw.Flushed)
+	require.Equal(t, "text/event-stream", rw.Header().Get("Content-Type"))
+	require.Equal(t, "no-cache", rw.Header().Get("Cache-Control"))
+	require.Equal(
+		t,
+		"id: 41\nevent: update\nretry: 3000\ndata: line one\ndata: line two\n\ndata: plain\n\n",
+		rw.Body.String(),
+	)
+}
+
+func TestEventStream_SanitizesFields(t *testing.T) {
+	router := New(WithNoData)
+	router.Get("/events", func(ctx context.Context, r *Request[NoData]) Response {
+		return EventStream(ctx, r, func(ctx context.Context, events *EventWriter) error {
+			return events.Send(Event{ID: "1\n2", Event: "a\r\nb", Data: "x\r\ny"})
+		})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, "id: 12\nevent: ab\ndata: x\ndata: y\n\n", rw.Body.String())
+}
+
+func TestEventStream_HeartbeatAndDisconnect(t *testing.T) {
+	done := make(chan error, 1)
+
+	router := New(WithNoData)
+	router.Get("/events", func(ctx context.Context, r *Request[NoData]) Response {
+		config := EventStreamConfig{HeartbeatInterval: 10 * time.Millisecond}
+
+		return EventStreamWithConfig(ctx, r, config, func(ctx context.Context, events *EventWriter) error {
+			<-ctx.Done()
+			err := events.Send(Event{Data: "too late"})
+			done <- err
+
+			return err
+		})
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	require.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	reader := bufio.NewReader(res.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, ": heartbeat\n", line)
+
+	cancel()
+
+	select {
+	case err := <-done:
+		require.True(t, errors.Is(err, context.Canceled))
+	case <-time.After(5 * time.Second):
+		t.Fatal("event stream was not canceled")
+	}
+}
+
+// heartbeatRecorder blocks heartbeat writes, recording whether any finish
+// after the response has been served.
+type heartbeatRecorder struct {
+	*httptest.ResponseRecorder
+	writing      chan struct{}
+	served       atomic.Bool
+	writtenAfter atomic.Bool
+}
+
+func (hr *heartbeatRecorder) Write(b []byte) (int, error) {
+	if strings.Contains(string(b), "heartbeat") {
+		select {
+		case hr.writing <- struct{}{}:
+		default:
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if hr.served.Load() {
+		hr.writtenAfter.Store(true)
+	}
+
+	return hr.ResponseRecorder.Write(b)
+}
+
+func TestEventStream_HeartbeatStopsBeforeReturning(t *testing.T) {
+	rw := &heartbeatRecorder{ResponseRecorder: httptest.NewRecorder(), writing: make(chan struct{}, 1)}
+
+	router := New(WithNoData)
+	router.Get("/events", func(ctx context.Context, r *Request[NoData]) Response {
+		config := EventStreamConfig{HeartbeatInterval: time.Millisecond}
+
+		return EventStreamWithConfig(ctx, r, config, func(ctx context.Context, events *EventWriter) error {
+			<-rw.writing
+			return nil
+		})
+	})
+
+	router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/events", nil))
+	rw.served.Store(true)
+
+	time.Sleep(50 * time.Millisecond)
+	require.False(t, rw.writtenAfter.Load(), "heartbeat was written after the response was served")
+}
+
+func TestEventStream_Head(t *testing.T) {
+	router := New(WithNoData)
+	router.Get("/events", func(ctx context.Context, r *Request[NoData]) Response {
+		return EventStream(ctx, r, func(ctx context.Context, events *EventWriter) error {
+			return events.Send(Event{Data: "hello"})
+		})
+	})
+
+	req := httptest.NewRequest(http.MethodHead, "/events", nil)
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.True(t, strings.HasPrefix(rw.Header().Get("Content-Type"), "text/event-stream"))
+	require.Empty(t, rw.Body.String())
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math/bits"
+	"mime/multipart"
 	"reflect"
 	"strconv"
 )
@@ -12,7 +13,20 @@ var ErrNotSupported = errors.New("Type not supported")
 
 type Decoder struct{}
 
+var fileHeaderType = reflect.TypeOf((*multipart.FileHeader)(nil))
+
 func (fd *Decoder) Decode(target any, src map[string][]string) error {
+	return fd.decode(target, src, nil)
+}
+
+// DecodeMultipart decodes the values and files of a multipart form into
+// target. Files are decoded into fields of type *multipart.FileHeader or
+// []*multipart.FileHeader.
+func (fd *Decoder) DecodeMultipart(target any, form *multipart.Form) error {
+	return fd.decode(target, form.Value, form.File)
+}
+
+func (fd *Decoder) decode(target any, src map[string][]string, files map[string][]*multipart.FileHeader) error {
 	value := reflect.ValueOf(target)
 	kind := value.Kind()
 
@@ -25,14 +39,14 @@ func (fd *Decoder) Decode(target any, src map[string][]string) error {
 
 	switch kind {
 	case reflect.Struct:
-		err := decodeStruct(target, value, src)
+		err := decodeStruct(target, value, src, files)
 		return err
 	default:
 		return fmt.Errorf("Decode not implemented for: %s", value.Kind())
 	}
 }
 
-func decodeStruct(target any, value reflect.Value, src map[string][]string) error {
+func decodeStruct(target any, value reflect.Value, src map[string][]string, files map[string][]*multipart.FileHeader) error {
 	for i := 0; i < value.NumField(); i++ {
 		field := value.Field(i)
 
@@ -47,6 +61,10 @@ func decodeStruct(target any, value reflect.Value, src map[string][]string) erro
 			continue
 		}
 
+		if decodeFiles(field, files[tag]) {
+			continue
+		}
+
 		fieldValue, ok := src[tag]
 
 		if !ok {
@@ -93,6 +111,25 @@ func decodeStruct(target any, value reflect.Value, src map[string][]string) erro
 	return nil
 }
 
+// decodeFiles sets field to the given files if it is a *multipart.FileHeader
+// or []*multipart.FileHeader, returning false for fields of other types.
+func decodeFiles(field reflect.Value, files []*multipart.FileHeader) bool {
+	switch field.Type() {
+	case fileHeaderType:
+		if len(files) > 0 {
+			field.Set(reflect.ValueOf(files[0]))
+		}
+	case reflect.SliceOf(fileHeaderType):
+		if len(files) > 0 {
+			field.Set(reflect.ValueOf(files))
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
 func decodeSlice(fieldType reflect.Type, values []string) (reflect.Value, error) {
 	var slice reflect.Value
 	if fieldType.Kind() == reflect.Pointer {
@@ -1,6 +1,7 @@
 package formula
 
 import (
+	"mime/multipart"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -173,3 +174,32 @@ func TestInvalidTypes(t *testing.T) {
 	require.Error(t, decoder.Decode(1, data))
 	require.Error(t, decoder.Decode("omg", data))
 }
+
+func TestDecodeMultipart(t *testing.T) {
+	target := struct {
+		Name        string                  `param:"name"`
+		Avatar      *multipart.FileHeader   `param:"avatar"`
+		Attachments []*multipart.FileHeader `param:"attachments"`
+		Missing     *multipart.FileHeader   `param:"missing"`
+	}{}
+
+	avatar := &multipart.FileHeader{Filename: "avatar.png"}
+	attachments := []*multipart.FileHeader{{Filename: "a.txt"}, {Filename: "b.txt"}}
+
+	form := &multipart.Form{
+		Value: map[string][]string{"name": {"Fox Mulder"}},
+		File: map[string][]*multipart.FileHeader{
+			"avatar":      {avatar},
+			"attachments": attachments,
+		},
+	}
+
+	decoder := Decoder{}
+	err := decoder.DecodeMultipart(&target, form)
+	require.NoError(t, err)
+
+	require.Equal(t, "Fox Mulder", target.Name)
+	require.Same(t, avatar, target.Avatar)
+	require.Equal(t, attachments, target.Attachments)
+	require.Nil(t, target.Missing)
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"net/http"
 	"regexp"
+	"time"
 )
 
 type dispatchable[T any] interface {
-	dispatch(r *RootRequest) (bool, *RouteData, func(context.Context, *Request[T]) Response)
+	routeEntries() []*routeEntry[T]
+	describe(id string, host string, befores int) RouteList
 }
 
 var _ dispatchable[NoData] = (*RouteGroup[NoData, NoData])(nil)
@@ -17,16 +19,31 @@ var _ dispatchable[NoData] = (*RouteGroup[NoData, NoData])(nil)
 type registerable[Data any] interface {
 	register(dispatchable[Data])
 	prefix() string
+	table() *routeTable
 }
 
 // RouteGroup represents a collection of routes that share a common set of
-// Around/Before/After callbacks and Action type (T)
+// Around/Before/After callbacks and Data type
 type RouteGroup[ParentData any, Data any] struct {
 	routes      []*Route[Data]
 	dataCreator func(ctx context.Context, r *Request[ParentData]) (context.Context, Data)
 	subgroups   []dispatchable[Data]
 	befores     []BeforeFunc[Data]
+	afters      []AfterFunc[Data]
+	// arounds holds the AroundFuncs added via Around, which share the
+	// signature of BeforeFuncs.
+	arounds     []BeforeFunc[Data]
 	routePrefix string
+	tree        *routeTable
+	// host restricts the group's routes to requests matching a host pattern.
+	host *hostPattern
+	// renderer adds default data to templates rendered by the group's routes.
+	renderer *Renderer[Data]
+	// errorHandler maps errors returned by the group's routes to responses.
+	errorHandler ErrorHandler[Data]
+	limits       limits
+	// middlewares holds the http middleware added via Use.
+	middlewares []Middleware
 }
 
 // SubRouter creates a new grouping of routes that will be routed to in addition
@@ -100,6 +117,7 @@ func Group[
 		},
 	}
 	group.routePrefix = parent.prefix()
+	group.tree = parent.table()
 	parent.register(group)
 
 	return group
@@ -117,14 +135,16 @@ func GroupWithContext[
 ) *RouteGroup[ParentData, Data] {
 	group := &RouteGroup[ParentData, Data]{routes: make([]*Route[Data], 0), dataCreator: creator}
 	group.routePrefix = parent.prefix()
+	group.tree = parent.table()
 	parent.register(group)
 
 	return group
 }
 
 // Match defines a new Route that responds to requests that match the given
-// method and path.
-func (g *RouteGroup[ParentData, Data]) Match(method string, path string, handler HandlerFunc[Data]) {
+// method and path. The route can be configured with RouteOptions, e.g.
+// WithName.
+func (g *RouteGroup[ParentData, Data]) Match(method string, path string, handler HandlerFunc[Data], opts ...RouteOption) {
 	if path == "/" {
 		path = g.routePrefix
 	} else {
@@ -135,84 +155,225 @@ func (g *RouteGroup[ParentData, Data]) Match(method string, path string, handler
 		path = "/"
 	}
 
-	g.routes = append(g.routes, newRoute(method, path, handler))
+	options := newRouteOptions(opts)
+	route := newRoute(method, path, handler)
+	route.Name = options.name
+	route.Metadata = options.metadata
+	route.limits = options.limits
+	route.befores = routeBefores[Data](path, options.befores)
+	route.middlewares = options.middlewares
+
+	if route.Name != "" {
+		g.tree.name(route.Name, route.variants)
+	}
+
+	g.routes = append(g.routes, route)
+	g.tree.invalidate()
 }
 
 // Defines a new Route that responds to GET requests.
-func (g *RouteGroup[ParentData, Data]) Get(path string, handler HandlerFunc[Data]) {
-	g.Match(http.MethodGet, path, handler)
+func (g *RouteGroup[ParentData, Data]) Get(path string, handler HandlerFunc[Data], opts ...RouteOption) {
+	g.Match(http.MethodGet, path, handler, opts...)
 }
 
 // Defines a new Route that responds to POST requests.
-func (g *RouteGroup[ParentData, Data]) Post(path string, handler HandlerFunc[Data]) {
-	g.Match(http.MethodPost, path, handler)
+func (g *RouteGroup[ParentData, Data]) Post(path string, handler HandlerFunc[Data], opts ...RouteOption) {
+	g.Match(http.MethodPost, path, handler, opts...)
 }
 
 // Defines a new Route that responds to PUT requests.
-func (t *RouteGroup[ParentData, Data]) Put(path string, handler HandlerFunc[Data]) {
-	t.Match(http.MethodPut, path, handler)
+func (t *RouteGroup[ParentData, Data]) Put(path string, handler HandlerFunc[Data], opts ...RouteOption) {
+	t.Match(http.MethodPut, path, handler, opts...)
 }
 
 // Defines a new Route that responds to PATCH requests.
-func (g *RouteGroup[ParentData, Data]) Patch(path string, handler HandlerFunc[Data]) {
-	g.Match(http.MethodPatch, path, handler)
+func (g *RouteGroup[ParentData, Data]) Patch(path string, handler HandlerFunc[Data], opts ...RouteOption) {
+	g.Match(http.MethodPatch, path, handler, opts...)
 }
 
 // Defines a new Route that responds to DELETE requests.
-func (g *RouteGroup[ParentData, Data]) Delete(path string, handler HandlerFunc[Data]) {
-	g.Match(http.MethodDelete, path, handler)
+func (g *RouteGroup[ParentData, Data]) Delete(path string, handler HandlerFunc[Data], opts ...RouteOption) {
+	g.Match(http.MethodDelete, path, handler, opts...)
+}
+
+// Defines a new Route that responds to HEAD requests. HEAD requests are
+// handled by GET routes when no HEAD route is defined.
+func (g *RouteGroup[ParentData, Data]) Head(path string, handler HandlerFunc[Data], opts ...RouteOption) {
+	g.Match(http.MethodHead, path, handler, opts...)
+}
+
+// Defines a new Route that responds to OPTIONS requests. OPTIONS requests are
+// answered with the allowed methods when no OPTIONS route is defined.
+func (g *RouteGroup[ParentData, Data]) Options(path string, handler HandlerFunc[Data], opts ...RouteOption) {
+	g.Match(http.MethodOptions, path, handler, opts...)
+}
+
+// MaxBodySize limits the size of request bodies sent to the group's routes to
+// n bytes, overriding the limit of its parent. Requests with larger bodies
+// receive a 413 Request Entity Too Large. A negative value removes the limit.
+func (g *RouteGroup[ParentData, Data]) MaxBodySize(n int64) {
+	g.limits.maxBodySize = n
+}
+
+// ReadTimeout limits how long clients have to send the request body to the
+// group's routes, overriding the timeout of its parent. Requests whose body
+// isn't received in time receive a 408 Request Timeout. A negative value
+// removes the timeout.
+func (g *RouteGroup[ParentData, Data]) ReadTimeout(timeout time.Duration) {
+	g.limits.readTimeout = timeout
+}
+
+// Timeout limits how long the group's routes have to return a response,
+// overriding the timeout of its parent. A negative value removes the timeout.
+//
+// The context passed to BeforeFuncs and handlers is canceled once the timeout
+// elapses and, if the handler hasn't returned, the response of
+// Router.TimedOut is sent instead. The handler's response is discarded when
+// it eventually returns. Since the context remains canceled while the
+// response is written, long-lived responses like event streams should
+// disable the timeout via WithTimeout(-1).
+//
+// Handlers with a timeout run in their own goroutine, so panics are re-raised
+// as a *Panic holding the stack of the handler's goroutine.
+func (g *RouteGroup[ParentData, Data]) Timeout(timeout time.Duration) {
+	g.limits.timeout = timeout
 }
 
-// Implements Dispatchable so groups can be registered on routers
-func (g *RouteGroup[ParentData, Data]) dispatch(rootRequest *RootRequest) (bool, *RouteData, func(context.Context, *Request[ParentData]) Response) {
-	handler, routeData := g.routeFor(rootRequest)
-	if handler == nil {
-		return false, nil, nil
+// routeEntries implements dispatchable so groups can be registered on routers.
+// Each route in the group and its subgroups is returned with a handler that
+// runs this group's data creator and BeforeFuncs before calling the route's
+// handler.
+func (g *RouteGroup[ParentData, Data]) routeEntries() []*routeEntry[ParentData] {
+	entries := make([]*routeEntry[ParentData], 0, len(g.routes))
+
+	for _, route := range g.routes {
+		handler := g.wrap(chainBefores(route.befores, route.handler))
+
+		for _, segments := range route.variants {
+			entries = append(entries, &routeEntry[ParentData]{
+				name:        route.Name,
+				method:      route.Method,
+				path:        route.Raw,
+				segments:    segments,
+				hosts:       g.hosts(nil),
+				limits:      []*limits{&g.limits, &route.limits},
+				middlewares: g.withMiddlewares(route.middlewares),
+				metadata:    route.Metadata,
+				handler:     handler,
+			})
+		}
 	}
 
-	return true, routeData, func(ctx context.Context, req *Request[ParentData]) Response {
-		ctx, data := g.dataCreator(ctx, req)
-		newReq := NewRequest(rootRequest.originalRequest, data, routeData)
+	for _, group := range g.subgroups {
+		for _, entry := range group.routeEntries() {
+			entries = append(entries, &routeEntry[ParentData]{
+				name:        entry.name,
+				method:      entry.method,
+				path:        entry.path,
+				segments:    entry.segments,
+				hosts:       g.hosts(entry.hosts),
+				limits:      append([]*limits{&g.limits}, entry.limits...),
+				middlewares: g.withMiddlewares(entry.middlewares),
+				metadata:    entry.metadata,
+				handler:     g.wrap(entry.handler),
+			})
+		}
+	}
 
-		routeHandler := func(ctx context.Context, req *Request[Data]) Response { return handler(ctx, req) }
+	return entries
+}
 
-		for i := len(g.befores) - 1; i >= 0; i-- {
-			currentHandler := routeHandler
-			before := g.befores[i]
+// hosts returns the given host patterns with the group's host pattern
+// appended, if it has one.
+func (g *RouteGroup[ParentData, Data]) hosts(hosts []*hostPattern) []*hostPattern {
+	if g.host == nil {
+		return hosts
+	}
+
+	return append(hosts[:len(hosts):len(hosts)], g.host)
+}
+
+// withMiddlewares returns the group's middleware followed by middlewares.
+func (g *RouteGroup[ParentData, Data]) withMiddlewares(middlewares []Middleware) []Middleware {
+	if len(g.middlewares) == 0 {
+		return middlewares
+	}
+
+	return append(g.middlewares[:len(g.middlewares):len(g.middlewares)], middlewares...)
+}
 
-			routeHandler = func(ctx context.Context, req *Request[Data]) Response {
-				return before(ctx, req, func(ctx context.Context) Response {
-					return currentHandler(ctx, req)
-				})
+// wrap returns a handler that creates the group's data from the parent
+// request and calls handler wrapped in the group's AroundFuncs, BeforeFuncs,
+// and AfterFuncs.
+func (g *RouteGroup[ParentData, Data]) wrap(handler func(context.Context, *Request[Data]) Response) func(context.Context, *Request[ParentData]) Response {
+	return func(ctx context.Context, req *Request[ParentData]) Response {
+		ctx, data := g.dataCreator(ctx, req)
+		newReq := NewRequest(req.originalRequest, data, req.routeData)
+
+		routeHandler := chainBefores(g.befores, handler)
+		withAfters := func(ctx context.Context, req *Request[Data]) Response {
+			res := routeHandler(g.renderer.withResolver(ctx, req), req)
+			res = g.resolve(ctx, req, g.handleError(ctx, req, res))
+
+			for _, after := range g.afters {
+				res = g.resolve(ctx, req, after(ctx, req, res))
 			}
+
+			return res
 		}
 
-		return routeHandler(ctx, newReq)
+		return chainBefores(g.arounds, withAfters)(ctx, newReq)
 	}
 }
 
-func (g *RouteGroup[ParentData, Data]) routeFor(req *RootRequest) (HandlerFunc[Data], *RouteData) {
-	for _, route := range g.routes {
-		if ok, params := route.IsMatch(req); ok {
-			return route.handler, &RouteData{Params: params, HandlerPath: route.Raw}
-		}
+// resolve resolves template responses using the Renderer attached via
+// Router.Renderer. Templates are resolved before the group's AfterFuncs run
+// so that AfterFuncs reading the response see the template rendered with its
+// default data.
+func (g *RouteGroup[ParentData, Data]) resolve(ctx context.Context, req *Request[Data], res Response) Response {
+	if g.renderer != nil {
+		return g.renderer.resolve(ctx, req, res)
 	}
 
-	for _, group := range g.subgroups {
-		if ok, routeData, handler := group.dispatch(req); ok {
-			return func(ctx context.Context, req *Request[Data]) Response {
-				return handler(ctx, req)
-			}, routeData
+	return resolveTemplate(ctx, res)
+}
+
+// handleError passes the error of responses returned by ErrorResponse to the
+// group's ErrorHandler, if it has one. Otherwise res is returned so that the
+// error can be handled by a parent group.
+func (g *RouteGroup[ParentData, Data]) handleError(ctx context.Context, req *Request[Data], res Response) Response {
+	errRes, ok := res.(*errorResponse)
+	if !ok || g.errorHandler == nil {
+		return res
+	}
+
+	logHandlerError(ctx, errRes.err)
+
+	return g.errorHandler(ctx, req, errRes.err)
+}
+
+// chainBefores returns a handler that calls befores, in order, before calling
+// handler.
+func chainBefores[Data any](befores []BeforeFunc[Data], handler func(context.Context, *Request[Data]) Response) func(context.Context, *Request[Data]) Response {
+	for i := len(befores) - 1; i >= 0; i-- {
+		currentHandler := handler
+		before := befores[i]
+
+		handler = func(ctx context.Context, req *Request[Data]) Response {
+			return before(ctx, req, func(ctx context.Context) Response {
+				return currentHandler(ctx, req)
+			})
 		}
 	}
 
-	return nil, nil
+	return handler
 }
 
 // register implements the registerable interface and allows subgroups to be
 // registered and routed to.
 func (g *RouteGroup[ParentData, Data]) register(subgroup dispatchable[Data]) {
 	g.subgroups = append(g.subgroups, subgroup)
+	g.tree.invalidate()
 }
 
 // prefix implements the registerable interface and allows subgroups to register
@@ -221,6 +382,12 @@ func (g *RouteGroup[ParentData, Data]) prefix() string {
 	return g.routePrefix
 }
 
+// table implements the registerable interface and allows subgroups to mark
+// the router's compiled routes as stale when routes are added.
+func (g *RouteGroup[ParentData, Data]) table() *routeTable {
+	return g.tree
+}
+
 var trailingSlash = regexp.MustCompile("/+$")
 var leadingSlash = regexp.MustCompile("^/+")
 
@@ -234,3 +401,50 @@ func joinPath(left string, right string) string {
 func (r *RouteGroup[ParentData, Data]) Before(before BeforeFunc[Data]) {
 	r.befores = append(r.befores, before)
 }
+
+// After adds an AfterFunc that is called with the Response of each of the
+// group's routes, including those of its subgroups. The Response returned by
+// the AfterFunc replaces the original, so AfterFuncs can set headers based on
+// the response status, write sessions, or record metrics.
+//
+// AfterFuncs are called in the order they are added, after the group's
+// BeforeFuncs and handler, including when a BeforeFunc halts the request by
+// not calling next. AfterFuncs aren't called if the handler panics.
+func (r *RouteGroup[ParentData, Data]) After(after AfterFunc[Data]) {
+	r.afters = append(r.afters, after)
+}
+
+// Around adds an AroundFunc that wraps each of the group's routes, including
+// those of its subgroups. AroundFuncs are called in the order they are added,
+// before the group's BeforeFuncs, and receive the Response after the group's
+// AfterFuncs have been called.
+//
+// For each group the request passes through, the order is:
+//
+//	AroundFuncs -> BeforeFuncs -> subgroups and handler -> AfterFuncs
+func (r *RouteGroup[ParentData, Data]) Around(around AroundFunc[Data]) {
+	r.arounds = append(r.arounds, BeforeFunc[Data](around))
+}
+
+// ErrorHandler sets the handler used to map errors returned by the group's
+// routes, including those of its subgroups, to a Response. Errors are returned
+// by handlers wrapped with HandleErrors, or by returning ErrorResponse.
+//
+// Errors are handled by the closest group with an ErrorHandler, before that
+// group's AfterFuncs are called. Errors that aren't handled are responded to
+// with the status returned by ErrorStatus. Either way, the error is logged.
+func (r *RouteGroup[ParentData, Data]) ErrorHandler(handler ErrorHandler[Data]) {
+	r.errorHandler = handler
+}
+
+// Use adds net/http style middleware that is called for each request routed
+// to the group's routes, including those of its subgroups. Unlike
+// Router.Use, the middleware is only called once a route has matched, after
+// the middleware of the router and any parent groups.
+//
+// Middleware is called in the order that they are added. Middleware must call
+// next in order to continue the request, otherwise the request is halted.
+func (r *RouteGroup[ParentData, Data]) Use(middleware Middleware) {
+	r.middlewares = append(r.middlewares, middleware)
+	r.tree.invalidate()
+}
@@ -19,11 +19,13 @@ func TestGroup_Routes(t *testing.T) {
 	testCases := map[string]struct {
 		method string
 	}{
-		"Get":    {method: http.MethodGet},
-		"Post":   {method: http.MethodPost},
-		"Put":    {method: http.MethodPut},
-		"Patch":  {method: http.MethodPatch},
-		"Delete": {method: http.MethodDelete},
+		"Get":     {method: http.MethodGet},
+		"Post":    {method: http.MethodPost},
+		"Put":     {method: http.MethodPut},
+		"Patch":   {method: http.MethodPatch},
+		"Delete":  {method: http.MethodDelete},
+		"Head":    {method: http.MethodHead},
+		"Options": {method: http.MethodOptions},
 	}
 	router := New(WithNoData)
 
@@ -239,3 +241,244 @@ func TestGroup_Before_NestedGroup(t *testing.T) {
 	require.Equal(t, "hello Fox Mulder", rw.Body.String())
 	require.Equal(t, "wow", rw.Header().Get("x-from-middleware"))
 }
+
+func TestGroup_Use(t *testing.T) {
+	var calls []string
+	middleware := func(name string) Middleware {
+		return func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+			calls = append(calls, name)
+			next(rw, r)
+		}
+	}
+
+	router := New(WithNoData)
+	router.Use(middleware("router"))
+
+	group := Group(router, func(_ *Request[NoData]) MyData { return MyData{} })
+	group.Use(middleware("group"))
+
+	subgroup := Group(group, func(r *Request[MyData]) MyData { return r.Data })
+	subgroup.Use(middleware("subgroup"))
+	subgroup.Get("/nested", func(ctx context.Context, r *Request[MyData]) Response {
+		calls = append(calls, "handler")
+		return OK()
+	}, WithMiddleware(middleware("route")))
+
+	router.Get("/root", func(ctx context.Context, r *Request[NoData]) Response {
+		calls = append(calls, "handler")
+		return OK()
+	})
+
+	testCases := map[string]struct {
+		path  string
+		calls []string
+	}{
+		"nested route": {path: "/nested", calls: []string{"router", "group", "subgroup", "route", "handler"}},
+		"root route":   {path: "/root", calls: []string{"router", "handler"}},
+		"missing":      {path: "/missing", calls: []string{"router"}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			calls = nil
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			rw := httptest.NewRecorder()
+			router.ServeHTTP(rw, req)
+
+			require.Equal(t, tc.calls, calls)
+		})
+	}
+}
+
+func TestGroup_WithBefore(t *testing.T) {
+	var calls []string
+
+	router := New(WithNoData)
+	group := Group(router, func(_ *Request[NoData]) MyData { return MyData{Value: 1} })
+	group.Before(func(ctx context.Context, r *Request[MyData], next Next) Response {
+		calls = append(calls, "group")
+		return next(ctx)
+	})
+
+	requireAdmin := func(ctx context.Context, r *Request[MyData], next Next) Response {
+		calls = append(calls, "route")
+		if r.Request().Header.Get("X-Admin") == "" {
+			return StringResponse(http.StatusForbidden, "forbidden")
+		}
+
+		return next(ctx)
+	}
+
+	group.Get("/admin", func(ctx context.Context, r *Request[MyData]) Response {
+		calls = append(calls, "handler")
+		return StringResponse(http.StatusOK, "admin")
+	}, WithBefore(requireAdmin))
+
+	group.Get("/public", func(ctx context.Context, r *Request[MyData]) Response {
+		return StringResponse(http.StatusOK, "public")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusForbidden, rw.Code)
+	require.Equal(t, []string{"group", "route"}, calls)
+
+	calls = nil
+	req = httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set("X-Admin", "true")
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, []string{"group", "route", "handler"}, calls)
+
+	calls = nil
+	req = httptest.NewRequest(http.MethodGet, "/public", nil)
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, []string{"group"}, calls)
+}
+
+func TestGroup_WithBefore_WrongDataType(t *testing.T) {
+	router := New(WithNoData)
+	group := Group(router, func(_ *Request[NoData]) MyData { return MyData{} })
+
+	require.PanicsWithValue(
+		t,
+		"WithBefore for route /admin expects BeforeFuncs with data type medium.MyData, got medium.NoData",
+		func() {
+			group.Get("/admin", func(ctx context.Context, r *Request[MyData]) Response {
+				return OK()
+			}, WithBefore(func(ctx context.Context, r *Request[NoData], next Next) Response {
+				return next(ctx)
+			}))
+		},
+	)
+}
+
+func TestGroup_WithMetadata(t *testing.T) {
+	router := New(WithNoData)
+	group := Group(router, func(_ *Request[NoData]) MyData { return MyData{} })
+	group.Before(func(ctx context.Context, r *Request[MyData], next Next) Response {
+		if permission, ok := r.Metadata("permission").(string); ok && r.Request().Header.Get("X-Permission") != permission {
+			return StringResponse(http.StatusForbidden, "forbidden")
+		}
+
+		return next(ctx)
+	})
+	group.Get("/reports", func(ctx context.Context, r *Request[MyData]) Response {
+		return OK()
+	}, WithMetadata("permission", "reports:read"), WithName("reports"))
+
+	req := httptest.NewRequest(http.MethodGet, "/reports", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusForbidden, rw.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/reports", nil)
+	req.Header.Set("X-Permission", "reports:read")
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+
+	routes := router.Routes()
+	require.Len(t, routes, 1)
+	require.Equal(t, "reports", routes[0].Name)
+	require.Equal(t, map[string]any{"permission": "reports:read"}, routes[0].Metadata)
+}
+
+func TestGroup_AfterAndAround(t *testing.T) {
+	var calls []string
+
+	router := New(WithNoData)
+	router.Around(func(ctx context.Context, r *Request[NoData], next Next) Response {
+		calls = append(calls, "router around")
+		res := next(ctx)
+		calls = append(calls, fmt.Sprintf("router around %d", res.Status()))
+
+		return res
+	})
+	router.After(func(ctx context.Context, r *Request[NoData], res Response) Response {
+		calls = append(calls, "router after")
+		res.Header().Set("X-Status", fmt.Sprint(res.Status()))
+
+		return res
+	})
+	router.Before(func(ctx context.Context, r *Request[NoData], next Next) Response {
+		calls = append(calls, "router before")
+		return next(ctx)
+	})
+
+	group := Group(router, func(_ *Request[NoData]) MyData { return MyData{Value: 1} })
+	group.After(func(ctx context.Context, r *Request[MyData], res Response) Response {
+		calls = append(calls, "group after")
+		require.Equal(t, 2, r.Data.Value)
+
+		if res.Status() == http.StatusNotFound {
+			return StringResponse(http.StatusGone, "gone")
+		}
+
+		return res
+	})
+	group.Before(func(ctx context.Context, r *Request[MyData], next Next) Response {
+		calls = append(calls, "group before")
+		r.Data.Value++
+
+		if r.Request().URL.Path == "/halt" {
+			return StringResponse(http.StatusForbidden, "forbidden")
+		}
+
+		return next(ctx)
+	})
+
+	handler := func(ctx context.Context, r *Request[MyData]) Response {
+		calls = append(calls, "handler")
+		return StringResponse(http.StatusNotFound, "not found")
+	}
+	group.Get("/handler", handler)
+	group.Get("/halt", handler)
+
+	testCases := map[string]struct {
+		path   string
+		status int
+		calls  []string
+	}{
+		"handler": {
+			path:   "/handler",
+			status: http.StatusGone,
+			calls: []string{
+				"router around", "router before", "group before", "handler",
+				"group after", "router after", "router around 410",
+			},
+		},
+		"halted by before": {
+			path:   "/halt",
+			status: http.StatusForbidden,
+			calls: []string{
+				"router around", "router before", "group before",
+				"group after", "router after", "router around 403",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			calls = nil
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			rw := httptest.NewRecorder()
+			router.ServeHTTP(rw, req)
+
+			require.Equal(t, tc.status, rw.Code)
+			require.Equal(t, fmt.Sprint(tc.status), rw.Header().Get("X-Status"))
+			require.Equal(t, tc.calls, calls)
+		})
+	}
+}
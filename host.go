@@ -0,0 +1,155 @@
+package medium
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// hostPattern matches the host of a request against a pattern such as
+// `:tenant.example.com`. Each `.` delimited label of the pattern is parsed
+// like a path segment, so labels can be literal values, named params, or
+// constrained and embedded params such as `:tenant<[a-z]+>` or `api-:region`.
+type hostPattern struct {
+	raw    string
+	labels []segment
+}
+
+func newHostPattern(pattern string) *hostPattern {
+	labels := strings.Split(pattern, ".")
+	segments := make([]segment, len(labels))
+
+	for i, label := range labels {
+		segments[i] = parseSegment(lowerLiterals(label))
+
+		if segments[i].kind == segmentCatchAll {
+			panic(fmt.Sprintf("catch-all is not supported in host pattern %s", pattern))
+		}
+	}
+
+	return &hostPattern{raw: pattern, labels: segments}
+}
+
+// lowerLiterals lowercases the literal parts of label so they match the
+// lowercased request host, leaving param names and constraints as written.
+func lowerLiterals(label string) string {
+	var lowered strings.Builder
+	literalStart := 0
+
+	for i := 0; i < len(label); {
+		if label[i] != ':' || i+1 == len(label) || !isParamNameChar(label[i+1]) {
+			i++
+			continue
+		}
+
+		lowered.WriteString(strings.ToLower(label[literalStart:i]))
+
+		end := i + 1
+		for end < len(label) && isParamNameChar(label[end]) {
+			end++
+		}
+
+		if end < len(label) && label[end] == '<' {
+			if closing := constraintEnd(label, end); closing != -1 {
+				end = closing + 1
+			}
+		}
+
+		lowered.WriteString(label[i:end])
+		i = end
+		literalStart = end
+	}
+
+	lowered.WriteString(strings.ToLower(label[literalStart:]))
+
+	return lowered.String()
+}
+
+// match matches host against the pattern, adding any matched params to
+// params. The port of host, if present, is ignored.
+func (hp *hostPattern) match(host string, params map[string]string) bool {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	values := make([]string, 0, len(hp.labels))
+
+	for i, label := range hp.labels {
+		part, rest, last := nextLabel(host)
+
+		if last != (i == len(hp.labels)-1) {
+			return false
+		}
+
+		var ok bool
+		if values, ok = label.match(part, values); !ok {
+			return false
+		}
+
+		host = rest
+	}
+
+	for name, value := range paramsFor(hp.labels, values) {
+		params[name] = value
+	}
+
+	return true
+}
+
+// nextLabel returns the first label of host, the remainder of host after the
+// label's trailing `.`, and whether the returned label was the last.
+func nextLabel(host string) (string, string, bool) {
+	i := strings.IndexByte(host, '.')
+	if i == -1 {
+		return host, "", true
+	}
+
+	return host[:i], host[i+1:], false
+}
+
+// HostRouter creates a new group of routes that only match requests whose
+// Host matches the given pattern. Patterns are made up of `.` delimited
+// labels which can be literal values or params, e.g. `:tenant.example.com`.
+// Matched host params are available via Request.Params alongside path params.
+//
+// Routes restricted to a host are preferred over routes with the same path
+// that match any host. The port of the request's Host is ignored when
+// matching.
+func HostRouter[
+	ParentData any,
+	Data any,
+	Parent registerable[ParentData],
+](
+	parent Parent,
+	pattern string,
+	creator func(r *Request[ParentData]) Data,
+) *RouteGroup[ParentData, Data] {
+	return HostRouterWithContext(parent, pattern, func(ctx context.Context, r *Request[ParentData]) (context.Context, Data) {
+		return ctx, creator(r)
+	})
+}
+
+// HostRouterWithContext has the same behavior as HostRouter but passes the
+// context to the data creator and requires a context to be returned.
+func HostRouterWithContext[
+	ParentData any,
+	Data any,
+	Parent registerable[ParentData],
+](
+	parent Parent,
+	pattern string,
+	creator func(ctx context.Context, r *Request[ParentData]) (context.Context, Data),
+) *RouteGroup[ParentData, Data] {
+	group := &RouteGroup[ParentData, Data]{
+		routes:      make([]*Route[Data], 0),
+		dataCreator: creator,
+		host:        newHostPattern(pattern),
+	}
+	group.routePrefix = parent.prefix()
+	group.tree = parent.table()
+	parent.register(group)
+
+	return group
+}
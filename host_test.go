@@ -0,0 +1,104 @@
+package medium
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostRouter(t *testing.T) {
+	router := New(WithNoData)
+
+	router.Get("/", func(ctx context.Context, r *Request[NoData]) Response {
+		return StringResponse(http.StatusOK, "marketing")
+	})
+
+	api := HostRouter(router, "api.example.com", WithNoData)
+	api.Get("/", func(ctx context.Context, r *Request[NoData]) Response {
+		return StringResponse(http.StatusOK, "api")
+	})
+	api.Post("/users", func(ctx context.Context, r *Request[NoData]) Response {
+		return StringResponse(http.StatusCreated, "created")
+	})
+
+	tenants := HostRouter(router, ":tenant<[a-z]+>.example.com", func(r *Request[NoData]) MyData {
+		require.NotEmpty(t, r.Params()["tenant"])
+		return MyData{Value: len(r.Params()["tenant"])}
+	})
+	projects := SubRouter(tenants, "/projects", func(r *Request[MyData]) MyData { return r.Data })
+	projects.Get("/:id", func(ctx context.Context, r *Request[MyData]) Response {
+		return StringResponse(http.StatusOK, fmt.Sprintf("%s project %s (%d)", r.Params()["tenant"], r.Params()["id"], r.Data.Value))
+	})
+
+	testCases := map[string]struct {
+		method string
+		host   string
+		path   string
+		code   int
+		body   string
+	}{
+		"fallback host":        {method: http.MethodGet, host: "example.com", path: "/", code: http.StatusOK, body: "marketing"},
+		"host route preferred": {method: http.MethodGet, host: "api.example.com", path: "/", code: http.StatusOK, body: "api"},
+		"literal host":         {method: http.MethodPost, host: "API.example.com:8080", path: "/users", code: http.StatusCreated, body: "created"},
+		"literal host miss":    {method: http.MethodPost, host: "example.com", path: "/users", code: http.StatusNotFound, body: "404 not found"},
+		"host params":          {method: http.MethodGet, host: "acme.example.com", path: "/projects/1", code: http.StatusOK, body: "acme project 1 (4)"},
+		"host constraint":      {method: http.MethodGet, host: "acme1.example.com", path: "/projects/1", code: http.StatusNotFound, body: "404 not found"},
+		"nested host miss":     {method: http.MethodGet, host: "a.b.example.com", path: "/projects/1", code: http.StatusNotFound, body: "404 not found"},
+		"method not allowed":   {method: http.MethodGet, host: "api.example.com", path: "/users", code: http.StatusMethodNotAllowed, body: "405 method not allowed"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.Host = tc.host
+			rw := httptest.NewRecorder()
+
+			router.ServeHTTP(rw, req)
+
+			require.Equal(t, tc.code, rw.Code)
+			require.Equal(t, tc.body, rw.Body.String())
+		})
+	}
+}
+
+func TestHostRouter_PatternCase(t *testing.T) {
+	router := New(WithNoData)
+
+	tenants := HostRouter(router, "Admin-:tenantID.Example.com", WithNoData)
+	tenants.Get("/", func(ctx context.Context, r *Request[NoData]) Response {
+		return StringResponse(http.StatusOK, "tenant "+r.Param("tenantID"))
+	})
+
+	regions := HostRouter(router, ":code<\\D+>.regions.example.com", WithNoData)
+	regions.Get("/", func(ctx context.Context, r *Request[NoData]) Response {
+		return StringResponse(http.StatusOK, "region "+r.Param("code"))
+	})
+
+	testCases := map[string]struct {
+		host string
+		code int
+		body string
+	}{
+		"param name case":          {host: "admin-acme.example.com", code: http.StatusOK, body: "tenant acme"},
+		"literal case":             {host: "ADMIN-acme.EXAMPLE.com", code: http.StatusOK, body: "tenant acme"},
+		"constraint case":          {host: "eu.regions.example.com", code: http.StatusOK, body: "region eu"},
+		"constraint case mismatch": {host: "42.regions.example.com", code: http.StatusNotFound, body: "404 not found"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = tc.host
+			rw := httptest.NewRecorder()
+
+			router.ServeHTTP(rw, req)
+
+			require.Equal(t, tc.code, rw.Code)
+			require.Equal(t, tc.body, rw.Body.String())
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package medium
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/blakewilliams/medium/mlog"
+)
+
+// DefaultMaxJSONBytes is the largest request body BindJSON will read unless
+// configured otherwise.
+const DefaultMaxJSONBytes = 1 << 20
+
+// JSONResponse is a Response that encodes a value as JSON. See JSON for more
+// information.
+type JSONResponse struct {
+	status int
+	header http.Header
+	value  any
+	indent string
+	stream bool
+
+	encoded bool
+	body    *bytes.Buffer
+	err     error
+}
+
+var _ writerResponse = (*JSONResponse)(nil)
+
+// JSON returns a response with the given status and v encoded as JSON.
+//
+// By default the value is encoded before the response is sent so that
+// encoding errors result in a 500 Internal Server Error. Use Stream to encode
+// large values directly to the client instead.
+func JSON(status int, v any) *JSONResponse {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json; charset=utf-8")
+
+	return &JSONResponse{status: status, header: header, value: v}
+}
+
+// Pretty indents the encoded JSON with two spaces, which is useful for
+// responses that are read by humans.
+func (jr *JSONResponse) Pretty() *JSONResponse {
+	jr.indent = "  "
+	jr.encoded = false
+
+	return jr
+}
+
+// Stream encodes the value directly to the client instead of buffering it.
+// Since the status is sent before encoding starts, encoding errors can't
+// change the status and are logged instead.
+func (jr *JSONResponse) Stream() *JSONResponse {
+	jr.stream = true
+
+	return jr
+}
+
+// Status returns the status code of the response, or 500 if the value could
+// not be encoded.
+func (jr *JSONResponse) Status() int {
+	if !jr.stream && jr.encode() != nil {
+		return http.StatusInternalServerError
+	}
+
+	return jr.status
+}
+
+// Header returns the header map for the response.
+func (jr *JSONResponse) Header() http.Header { return jr.header }
+
+// Body returns the encoded JSON. nil is returned for streaming responses and
+// values that could not be encoded.
+func (jr *JSONResponse) Body() io.Reader {
+	if jr.stream || jr.encode() != nil {
+		return nil
+	}
+
+	return bytes.NewReader(jr.body.Bytes())
+}
+
+// encode encodes the value into the response body, caching the result.
+func (jr *JSONResponse) encode() error {
+	if jr.encoded {
+		return jr.err
+	}
+
+	jr.encoded = true
+	jr.body = new(bytes.Buffer)
+	jr.err = jr.encoder(jr.body).Encode(jr.value)
+
+	return jr.err
+}
+
+func (jr *JSONResponse) encoder(w io.Writer) *json.Encoder {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", jr.indent)
+
+	return encoder
+}
+
+func (jr *JSONResponse) writeResponse(rw http.ResponseWriter, r *http.Request) {
+	if !jr.stream {
+		if err := jr.encode(); err != nil {
+			mlog.Error(r.Context(), "error encoding json response", mlog.Fields{"error": err})
+			http.Error(rw, "500 internal server error", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	rw.WriteHeader(jr.status)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if !jr.stream {
+		_, _ = rw.Write(jr.body.Bytes())
+		return
+	}
+
+	if err := jr.encoder(rw).Encode(jr.value); err != nil {
+		mlog.Error(r.Context(), "error streaming json response", mlog.Fields{"error": err})
+	}
+}
+
+// BindJSONConfig configures how request bodies are decoded by
+// BindJSONWithConfig.
+type BindJSONConfig struct {
+	// MaxBytes is the largest request body that will be read. Larger bodies
+	// result in a 413 Request Entity Too Large error. Defaults to
+	// DefaultMaxJSONBytes.
+	MaxBytes int64
+	// DisallowUnknownFields rejects bodies containing object keys that don't
+	// match a field in the destination type.
+	DisallowUnknownFields bool
+}
+
+// BindError is returned when a request body can't be bound. It can be
+// returned to the client directly via JSON, e.g. `JSON(err.Status, err)`.
+type BindError struct {
+	// Status is the HTTP status code that best describes the error.
+	Status int `json:"-"`
+	// Field is the path to the field that caused the error, e.g.
+	// `address.zip`, or empty if the error isn't specific to a field.
+	Field string `json:"field,omitempty"`
+	// Message describes the error.
+	Message string `json:"message"`
+	// Err is the underlying error.
+	Err error `json:"-"`
+}
+
+func (e *BindError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+
+	return e.Field + ": " + e.Message
+}
+
+func (e *BindError) Unwrap() error { return e.Err }
+
+// BindJSON decodes the request's JSON body into a new T using the default
+// BindJSONConfig. See BindJSONWithConfig for more information.
+func BindJSON[T any](r requestable) (T, error) {
+	return BindJSONWithConfig[T](r, BindJSONConfig{})
+}
+
+// BindJSONWithConfig decodes the request's JSON body into a new T. Errors are
+// returned as a *BindError describing the problem and which field, if any,
+// caused it.
+//
+// Requests with a Content-Type other than JSON are rejected with a 415, and
+// bodies larger than the configured limit are rejected with a 413. Bodies that
+// are empty, malformed, contain more than one value, or don't match T are
+// rejected with a 400.
+func BindJSONWithConfig[T any](r requestable, config BindJSONConfig) (T, error) {
+	var value T
+	req := r.Request()
+
+	if config.MaxBytes == 0 {
+		config.MaxBytes = DefaultMaxJSONBytes
+	}
+
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+			return value, &BindError{
+				Status:  http.StatusUnsupportedMediaType,
+				Message: fmt.Sprintf("unsupported content type %q, expected application/json", contentType),
+				Err:     err,
+			}
+		}
+	}
+
+	if req.Body == nil {
+		return value, &BindError{Status: http.StatusBadRequest, Message: "request body is empty"}
+	}
+
+	body := &limitedReader{r: req.Body, remaining: config.MaxBytes}
+	decoder := json.NewDecoder(body)
+	if config.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(&value); err != nil {
+		return value, bindErrorFor(err, body)
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		if body.exceeded {
+			return value, bindErrorFor(err, body)
+		}
+
+		return value, &BindError{Status: http.StatusBadRequest, Message: "request body must contain a single JSON value", Err: err}
+	}
+
+	return value, nil
+}
+
+// bindErrorFor converts an error returned by json.Decoder into a BindError.
+func bindErrorFor(err error, body *limitedReader) *BindError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case body.exceeded || errors.As(err, &maxBytesErr):
+		return &BindError{Status: http.StatusRequestEntityTooLarge, Message: "request body is too large", Err: err}
+	case errors.Is(err, io.EOF):
+		return &BindError{Status: http.StatusBadRequest, Message: "request body is empty", Err: err}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &BindError{Status: http.StatusBadRequest, Message: "request body contains malformed JSON", Err: err}
+	case errors.As(err, &syntaxErr):
+		return &BindError{
+			Status:  http.StatusBadRequest,
+			Message: fmt.Sprintf("request body contains malformed JSON at offset %d", syntaxErr.Offset),
+			Err:     err,
+		}
+	case errors.As(err, &typeErr):
+		return &BindError{
+			Status:  http.StatusBadRequest,
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("expected %s but got %s", typeErr.Type, typeErr.Value),
+			Err:     err,
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+
+		return &BindError{Status: http.StatusBadRequest, Field: field, Message: "unknown field", Err: err}
+	default:
+		return &BindError{Status: http.StatusBadRequest, Message: err.Error(), Err: err}
+	}
+}
+
+// limitedReader reads up to remaining bytes, recording whether the underlying
+// reader had more data.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if lr.remaining < 0 {
+		lr.exceeded = true
+		return 0, errors.New("request body is too large")
+	}
+
+	if int64(len(p)) > lr.remaining+1 {
+		p = p[:lr.remaining+1]
+	}
+
+	n, err := lr.r.Read(p)
+	lr.remaining -= int64(n)
+
+	if lr.remaining < 0 {
+		lr.exceeded = true
+		return n + int(lr.remaining), errors.New("request body is too large")
+	}
+
+	return n, err
+}
@@ -0,0 +1,213 @@
+package medium
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON(t *testing.T) {
+	router := New(WithNoData)
+	router.Get("/user", func(ctx context.Context, r *Request[NoData]) Response {
+		return JSON(http.StatusCreated, map[string]string{"name": "Fox Mulder"})
+	})
+	router.Get("/pretty", func(ctx context.Context, r *Request[NoData]) Response {
+		return JSON(http.StatusOK, map[string]int{"id": 1}).Pretty()
+	})
+	router.Get("/stream", func(ctx context.Context, r *Request[NoData]) Response {
+		return JSON(http.StatusOK, []int{1, 2, 3}).Stream()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/user", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusCreated, rw.Code)
+	require.Equal(t, "application/json; charset=utf-8", rw.Header().Get("Content-Type"))
+	require.Equal(t, "{\"name\":\"Fox Mulder\"}\n", rw.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/pretty", nil)
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, "{\n  \"id\": 1\n}\n", rw.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/stream", nil)
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "[1,2,3]\n", rw.Body.String())
+
+	req = httptest.NewRequest(http.MethodHead, "/user", nil)
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusCreated, rw.Code)
+	require.Empty(t, rw.Body.String())
+}
+
+func TestJSON_Body(t *testing.T) {
+	res := JSON(http.StatusOK, []string{"a"})
+
+	body, err := io.ReadAll(res.Body())
+	require.NoError(t, err)
+	require.Equal(t, "[\"a\"]\n", string(body))
+
+	body, err = io.ReadAll(res.Body())
+	require.NoError(t, err)
+	require.Equal(t, "[\"a\"]\n", string(body))
+}
+
+func TestJSON_EncodingError(t *testing.T) {
+	res := JSON(http.StatusOK, func() {})
+
+	require.Equal(t, http.StatusInternalServerError, res.Status())
+	require.Nil(t, res.Body())
+
+	router := New(WithNoData)
+	router.Get("/", func(ctx context.Context, r *Request[NoData]) Response {
+		return JSON(http.StatusOK, make(chan int))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusInternalServerError, rw.Code)
+}
+
+type bindAddress struct {
+	Zip string `json:"zip"`
+}
+
+type bindUser struct {
+	Name    string      `json:"name"`
+	Age     int         `json:"age"`
+	Address bindAddress `json:"address"`
+}
+
+func bindRequest(body string, contentType string) *Request[NoData] {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return NewRequest(req, NoData{}, nil)
+}
+
+func TestBindJSON(t *testing.T) {
+	user, err := BindJSON[bindUser](bindRequest(`{"name":"Dana","age":30,"address":{"zip":"20001"}}`, "application/json"))
+
+	require.NoError(t, err)
+	require.Equal(t, bindUser{Name: "Dana", Age: 30, Address: bindAddress{Zip: "20001"}}, user)
+
+	user, err = BindJSON[bindUser](bindRequest(`{"name":"Dana","extra":true}`, "application/vnd.api+json"))
+
+	require.NoError(t, err)
+	require.Equal(t, "Dana", user.Name)
+}
+
+func TestBindJSON_Errors(t *testing.T) {
+	testCases := map[string]struct {
+		body        string
+		contentType string
+		config      BindJSONConfig
+		status      int
+		field       string
+		message     string
+	}{
+		"wrong content type": {
+			body:        `{}`,
+			contentType: "text/plain",
+			status:      http.StatusUnsupportedMediaType,
+			message:     `unsupported content type "text/plain", expected application/json`,
+		},
+		"empty body": {
+			status:  http.StatusBadRequest,
+			message: "request body is empty",
+		},
+		"malformed": {
+			body:    `{"name": }`,
+			status:  http.StatusBadRequest,
+			message: "request body contains malformed JSON at offset 10",
+		},
+		"truncated": {
+			body:    `{"name": "Dana"`,
+			status:  http.StatusBadRequest,
+			message: "request body contains malformed JSON",
+		},
+		"wrong type": {
+			body:    `{"address": {"zip": 20001}}`,
+			status:  http.StatusBadRequest,
+			field:   "address.zip",
+			message: "expected string but got number",
+		},
+		"unknown field": {
+			body:    `{"name": "Dana", "rank": "agent"}`,
+			config:  BindJSONConfig{DisallowUnknownFields: true},
+			status:  http.StatusBadRequest,
+			field:   "rank",
+			message: "unknown field",
+		},
+		"multiple values": {
+			body:    `{} {}`,
+			status:  http.StatusBadRequest,
+			message: "request body must contain a single JSON value",
+		},
+		"too large": {
+			body:    `{"name": "` + strings.Repeat("a", 20) + `"}`,
+			config:  BindJSONConfig{MaxBytes: 16},
+			status:  http.StatusRequestEntityTooLarge,
+			message: "request body is too large",
+		},
+		"too large after value": {
+			body:    `{"age": 1}` + strings.Repeat(" ", 20),
+			config:  BindJSONConfig{MaxBytes: 16},
+			status:  http.StatusRequestEntityTooLarge,
+			message: "request body is too large",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := BindJSONWithConfig[bindUser](bindRequest(tc.body, tc.contentType), tc.config)
+
+			var bindErr *BindError
+			require.True(t, errors.As(err, &bindErr), "expected BindError, got %v", err)
+			require.Equal(t, tc.status, bindErr.Status)
+			require.Equal(t, tc.field, bindErr.Field)
+			require.Equal(t, tc.message, bindErr.Message)
+		})
+	}
+}
+
+func TestBindJSON_ErrorResponse(t *testing.T) {
+	router := New(WithNoData)
+	router.Post("/users", func(ctx context.Context, r *Request[NoData]) Response {
+		user, err := BindJSON[bindUser](r)
+		if err != nil {
+			var bindErr *BindError
+			if errors.As(err, &bindErr) {
+				return JSON(bindErr.Status, bindErr)
+			}
+
+			return StringResponse(http.StatusInternalServerError, "500 internal server error")
+		}
+
+		return JSON(http.StatusCreated, user)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"age": "old"}`))
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusBadRequest, rw.Code)
+	require.JSONEq(t, `{"field": "age", "message": "expected int but got string"}`, rw.Body.String())
+}
@@ -0,0 +1,130 @@
+package medium
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// limits holds the request body size limit, read timeout, and handler timeout
+// of a router, group, or route.
+type limits struct {
+	// maxBodySize is the largest request body that can be read. Zero means
+	// the limit is inherited and a negative value means no limit.
+	maxBodySize int64
+	// readTimeout is how long the client has to send the request body. Zero
+	// means the timeout is inherited and a negative value means no timeout.
+	readTimeout time.Duration
+	// timeout is how long the handler has to return a response. Zero means
+	// the timeout is inherited and a negative value means no timeout.
+	timeout time.Duration
+}
+
+// effectiveLimits returns the limits that apply to a route given the limits
+// of the route and each group it's defined in, ordered from the router to the
+// route. The innermost value that's set wins.
+func effectiveLimits(chain []*limits) limits {
+	var effective limits
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		if effective.maxBodySize == 0 {
+			effective.maxBodySize = chain[i].maxBodySize
+		}
+
+		if effective.readTimeout == 0 {
+			effective.readTimeout = chain[i].readTimeout
+		}
+
+		if effective.timeout == 0 {
+			effective.timeout = chain[i].timeout
+		}
+	}
+
+	return effective
+}
+
+// apply enforces the limits on the request before calling next. Requests with
+// bodies larger than the size limit receive a 413 Request Entity Too Large
+// and requests whose body isn't received before the read timeout receive a
+// 408 Request Timeout, regardless of the response returned by next.
+func (l limits) apply(rw http.ResponseWriter, r *http.Request, next func() Response) Response {
+	if l.maxBodySize <= 0 && l.readTimeout <= 0 {
+		return next()
+	}
+
+	if l.maxBodySize > 0 && r.ContentLength > l.maxBodySize {
+		return StringResponse(http.StatusRequestEntityTooLarge, "413 request entity too large")
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return next()
+	}
+
+	body := &limitedBody{ReadCloser: r.Body}
+	if l.maxBodySize > 0 {
+		body.ReadCloser = http.MaxBytesReader(rw, r.Body, l.maxBodySize)
+	}
+	r.Body = body
+
+	if l.readTimeout > 0 {
+		// Deadlines can't be set on every ResponseWriter, e.g. those used in
+		// tests, so the timeout is only enforced when supported.
+		rc := http.NewResponseController(rw)
+		if rc.SetReadDeadline(time.Now().Add(l.readTimeout)) == nil {
+			// The deadline must be cleared once the body has been read, since
+			// net/http cancels the request's context when a background read
+			// of the connection hits it.
+			body.done = func() { _ = rc.SetReadDeadline(time.Time{}) }
+		}
+	}
+
+	res := next()
+
+	// Keep the deadline after a failed read so the connection is closed
+	// instead of waiting on the rest of the body.
+	if body.err == nil {
+		body.finish()
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(body.err, &maxBytesErr):
+		return StringResponse(http.StatusRequestEntityTooLarge, "413 request entity too large")
+	case errors.Is(body.err, os.ErrDeadlineExceeded):
+		return StringResponse(http.StatusRequestTimeout, "408 request timeout")
+	}
+
+	return res
+}
+
+// limitedBody records the first error encountered reading the request body,
+// so that exceeding the limits can be reported even if the handler ignores
+// the error.
+type limitedBody struct {
+	io.ReadCloser
+	err error
+	// done is called once the body has been read without error.
+	done func()
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	switch {
+	case err == io.EOF:
+		b.finish()
+	case err != nil && b.err == nil:
+		b.err = err
+	}
+
+	return n, err
+}
+
+// finish calls done if it hasn't been called yet.
+func (b *limitedBody) finish() {
+	if b.done != nil {
+		b.done()
+		b.done = nil
+	}
+}
@@ -0,0 +1,160 @@
+package medium
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	echo := func(ctx context.Context, r *Request[NoData]) Response {
+		form, err := r.PostFormData()
+		if err != nil {
+			return StringResponse(http.StatusBadRequest, err.Error())
+		}
+
+		return StringResponse(http.StatusOK, url.Values(form).Get("name"))
+	}
+
+	router := New(WithNoData)
+	router.MaxBodySize(16)
+	router.Post("/small", echo)
+	router.Post("/large", echo, WithMaxBodySize(64))
+	router.Post("/unlimited", echo, WithMaxBodySize(-1))
+
+	group := Group(router, WithNoData)
+	group.MaxBodySize(32)
+	group.Post("/group", echo)
+
+	testCases := map[string]struct {
+		path   string
+		size   int
+		status int
+	}{
+		"within router limit":   {path: "/small", size: 5, status: http.StatusOK},
+		"over router limit":     {path: "/small", size: 20, status: http.StatusRequestEntityTooLarge},
+		"within route override": {path: "/large", size: 50, status: http.StatusOK},
+		"over route override":   {path: "/large", size: 70, status: http.StatusRequestEntityTooLarge},
+		"unlimited route":       {path: "/unlimited", size: 1000, status: http.StatusOK},
+		"within group override": {path: "/group", size: 20, status: http.StatusOK},
+		"over group override":   {path: "/group", size: 40, status: http.StatusRequestEntityTooLarge},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			body := "name=" + strings.Repeat("a", tc.size-5)
+
+			for _, chunked := range []bool{false, true} {
+				req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				if chunked {
+					// Hide the reader type so the body is read without a known
+					// length.
+					req.Body = io.NopCloser(struct{ io.Reader }{strings.NewReader(body)})
+					req.ContentLength = -1
+				}
+				rw := httptest.NewRecorder()
+
+				router.ServeHTTP(rw, req)
+
+				require.Equal(t, tc.status, rw.Code)
+				if tc.status == http.StatusRequestEntityTooLarge {
+					require.Equal(t, "413 request entity too large", rw.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestMaxBodySize_BindJSON(t *testing.T) {
+	router := New(WithNoData)
+	router.Post("/", func(ctx context.Context, r *Request[NoData]) Response {
+		_, err := BindJSON[map[string]string](r)
+		require.Equal(t, http.StatusRequestEntityTooLarge, err.(*BindError).Status)
+
+		return StringResponse(http.StatusBadRequest, err.Error())
+	}, WithMaxBodySize(8))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "too long"}`))
+	req.ContentLength = -1
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+}
+
+func TestReadTimeout(t *testing.T) {
+	router := New(WithNoData)
+	router.ReadTimeout(time.Hour)
+	router.Post("/upload", func(ctx context.Context, r *Request[NoData]) Response {
+		_, _ = io.ReadAll(r.Body())
+
+		return OK()
+	}, WithReadTimeout(50*time.Millisecond))
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("POST /upload HTTP/1.1\r\nHost: example.com\r\nContent-Length: 100\r\n\r\npartial"))
+	require.NoError(t, err)
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	res, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusRequestTimeout, res.StatusCode)
+}
+
+func TestReadTimeout_SlowHandler(t *testing.T) {
+	ctxErrs := make(chan error, 1)
+	slow := func(ctx context.Context, r *Request[NoData]) Response {
+		_, _ = io.ReadAll(r.Body())
+		time.Sleep(300 * time.Millisecond)
+		ctxErrs <- ctx.Err()
+
+		return OK()
+	}
+
+	router := New(WithNoData)
+	router.ReadTimeout(100 * time.Millisecond)
+	router.Get("/", slow)
+	router.Post("/", slow)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	testCases := map[string]struct {
+		method string
+		body   io.Reader
+	}{
+		"without body": {method: http.MethodGet},
+		"with body":    {method: http.MethodPost, body: strings.NewReader("name=fox")},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, server.URL, tc.body)
+			require.NoError(t, err)
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			require.NoError(t, <-ctxErrs)
+		})
+	}
+}
@@ -1,13 +1,11 @@
 package mail
 
 import (
-	"bytes"
 	"context"
 	"embed"
 	_ "embed"
-	"strconv"
+	"io/fs"
 
-	"github.com/blakewilliams/bat"
 	"github.com/blakewilliams/medium"
 )
 
@@ -15,69 +13,70 @@ import (
 var viewFS embed.FS
 
 func RegisterSentMailViewer[T any](router *medium.Router[T], mailer *Mailer) {
-	renderer := bat.NewEngine(bat.HTMLEscape)
-	err := renderer.AutoRegister(viewFS, "", ".html")
+	views, err := fs.Sub(viewFS, "views")
 	if err != nil {
 		panic(err)
 	}
 
-	router.Get("/_mailer", func(ctx context.Context, r *medium.Request[T]) medium.Response {
+	renderer, err := medium.NewRenderer[T](views, medium.RendererConfig{Layout: "layout.html"})
+	if err != nil {
+		panic(err)
+	}
+
+	router.Get("/_mailer", medium.HandleErrors(func(ctx context.Context, r *medium.Request[T]) (medium.Response, error) {
 		data := map[string]interface{}{
 			"SentMail": mailer.SentMail,
 		}
 
-		childContent := new(bytes.Buffer)
-		err := renderer.Render(childContent, "views/index.html", data)
-		if err != nil {
-			panic(err)
+		res := renderer.Render(200, "index.html", data)
+		if err := res.Err(); err != nil {
+			return nil, err
 		}
-		data["ChildContent"] = bat.Safe(childContent.String())
-
-		res := medium.NewResponse()
-		renderer.Render(res, "views/layout.html", data)
-
-		return res
-	})
 
-	router.Get("/_mailer/sent/:index", func(ctx context.Context, r *medium.Request[T]) medium.Response {
-		strIndex := r.Params()["index"]
-		index, err := strconv.Atoi(strIndex)
+		return res, nil
+	}))
 
+	router.Get("/_mailer/sent/:index", medium.HandleErrors(func(ctx context.Context, r *medium.Request[T]) (medium.Response, error) {
+		mail, index, err := sentMail(r, mailer)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 
 		data := map[string]interface{}{
-			"Mail":  mailer.SentMail[index],
+			"Mail":  mail,
 			"Index": index,
 		}
 
-		childContent := new(bytes.Buffer)
-		err = renderer.Render(childContent, "views/show.html", data)
-		if err != nil {
-			panic(err)
+		res := renderer.Render(200, "show.html", data)
+		if err := res.Err(); err != nil {
+			return nil, err
 		}
-		data["ChildContent"] = bat.Safe(childContent.String())
-
-		res := medium.NewResponse()
-		_ = renderer.Render(res, "views/layout.html", data)
 
-		return res
-	})
+		return res, nil
+	}))
 
-	router.Get("/_mailer/sent/:index/content/:contentIndex/body", func(ctx context.Context, r *medium.Request[T]) medium.Response {
-		strIndex := r.Params()["index"]
-		index, err := strconv.Atoi(strIndex)
+	router.Get("/_mailer/sent/:index/content/:contentIndex/body", medium.HandleErrors(func(ctx context.Context, r *medium.Request[T]) (medium.Response, error) {
+		mail, _, err := sentMail(r, mailer)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 
-		strContentIndex := r.Params()["contentIndex"]
-		contentIndex, err := strconv.Atoi(strContentIndex)
-		if err != nil {
-			panic(err)
+		contentIndex, err := r.ParamInt("contentIndex")
+		if err != nil || contentIndex < 0 || contentIndex >= len(mail.Contents) {
+			return nil, medium.NotFound("content not found")
 		}
 
-		return medium.StringResponse(200, mailer.SentMail[index].Contents[contentIndex].Body)
-	})
+		return medium.StringResponse(200, mail.Contents[contentIndex].Body), nil
+	}))
+}
+
+// sentMail returns the sent message identified by the index param, or a 404
+// error if there is no such message.
+func sentMail[T any](r *medium.Request[T], mailer *Mailer) (Message, int, error) {
+	index, err := r.ParamInt("index")
+	if err != nil || index < 0 || index >= len(mailer.SentMail) {
+		return Message{}, 0, medium.NotFound("sent mail not found")
+	}
+
+	return mailer.SentMail[index], index, nil
 }
@@ -109,3 +109,23 @@ func sentRenderer(tb testing.TB) *bat.Engine {
 
 	return engine
 }
+
+func TestSentViewer_NotFound(t *testing.T) {
+	r := medium.New(medium.WithNoData)
+	mailer := New(&FakeDeliverer{}, sentRenderer(t))
+	mailer.DevMode = true
+
+	RegisterSentMailViewer(r, mailer)
+
+	msg := mailer.NewMessage("Welcome!", "foo@bar.net")
+	require.NoError(t, msg.Template("index.html", nil))
+	require.NoError(t, mailer.Send(context.Background(), msg))
+
+	for _, path := range []string{"/_mailer/sent/1", "/_mailer/sent/-1", "/_mailer/sent/nope", "/_mailer/sent/0/content/5/body"} {
+		req := httptest.NewRequest("GET", path, nil)
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+
+		require.Equal(t, 404, res.Code, path)
+	}
+}
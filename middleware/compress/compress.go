@@ -0,0 +1,388 @@
+// Package compress provides a middleware that compresses response bodies
+// using an encoding accepted by the client.
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/blakewilliams/medium"
+)
+
+// DefaultMinSize is the smallest response body, in bytes, that is compressed
+// unless configured otherwise.
+const DefaultMinSize = 1024
+
+// DefaultSkipContentTypes holds the content types that are not compressed by
+// default since they're already compressed.
+var DefaultSkipContentTypes = []string{
+	"image/",
+	"audio/",
+	"video/",
+	"font/woff",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/x-bzip2",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"application/zstd",
+	"application/pdf",
+	"application/octet-stream",
+}
+
+// Encoder compresses response bodies using a content coding.
+type Encoder struct {
+	// Encoding is the content coding name, e.g. `gzip`, used to negotiate with
+	// the client's Accept-Encoding header.
+	Encoding string
+	// NewWriter returns a writer that compresses data written to w. If the
+	// returned writer has a `Flush() error` method it's called when the
+	// response is flushed.
+	NewWriter func(w io.Writer) io.WriteCloser
+}
+
+// Gzip compresses responses using gzip.
+var Gzip = Encoder{
+	Encoding: "gzip",
+	NewWriter: func(w io.Writer) io.WriteCloser {
+		return gzip.NewWriter(w)
+	},
+}
+
+// Deflate compresses responses using deflate.
+var Deflate = Encoder{
+	Encoding: "deflate",
+	NewWriter: func(w io.Writer) io.WriteCloser {
+		writer, _ := flate.NewWriter(w, flate.DefaultCompression)
+		return writer
+	},
+}
+
+// Config configures the middleware returned by MiddlewareWithConfig.
+type Config struct {
+	// Encoders holds the supported encoders in order of preference. Brotli or
+	// other encodings can be supported by providing an Encoder. Defaults to
+	// Gzip and Deflate.
+	Encoders []Encoder
+	// MinSize is the smallest response body, in bytes, that is compressed.
+	// Responses that are flushed before MinSize bytes are written are always
+	// compressed since their final size isn't known. Defaults to
+	// DefaultMinSize.
+	MinSize int
+	// SkipContentTypes holds content type prefixes that aren't compressed.
+	// Defaults to DefaultSkipContentTypes.
+	SkipContentTypes []string
+}
+
+// Middleware compresses responses using the default Config. See
+// MiddlewareWithConfig for more information.
+func Middleware(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	defaultMiddleware(rw, r, next)
+}
+
+var defaultMiddleware = MiddlewareWithConfig(Config{})
+
+// MiddlewareWithConfig returns a middleware that compresses response bodies
+// using the encoding that best matches the request's Accept-Encoding header.
+//
+// Responses are not compressed when they already have a Content-Encoding, are
+// a partial or bodiless response, have a skipped content type, or are smaller
+// than MinSize. Compressed responses have their Content-Length removed and a
+// strong ETag made weak, since the encoded body is no longer byte for byte
+// identical to the original. Vary: Accept-Encoding is set on every response
+// that could have been compressed.
+func MiddlewareWithConfig(config Config) medium.Middleware {
+	if config.Encoders == nil {
+		config.Encoders = []Encoder{Gzip, Deflate}
+	}
+
+	if config.MinSize == 0 {
+		config.MinSize = DefaultMinSize
+	}
+
+	if config.SkipContentTypes == nil {
+		config.SkipContentTypes = DefaultSkipContentTypes
+	}
+
+	return func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		// Upgraded connections, e.g. WebSockets, take over the connection and
+		// can't be compressed.
+		if r.Header.Get("Upgrade") != "" || r.Method == http.MethodHead {
+			next(rw, r)
+			return
+		}
+
+		encoder, ok := negotiate(r.Header.Values("Accept-Encoding"), config.Encoders)
+		if !ok {
+			rw.Header().Add("Vary", "Accept-Encoding")
+			next(rw, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: rw, config: config, encoder: encoder}
+		defer cw.close()
+
+		next(cw, r)
+	}
+}
+
+// compressWriter buffers the start of the response body to decide whether
+// the response should be compressed, then writes the remainder of the body
+// through the encoder when compressing.
+type compressWriter struct {
+	http.ResponseWriter
+	config  Config
+	encoder Encoder
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         bytes.Buffer
+	writer      io.WriteCloser
+}
+
+var _ http.Flusher = (*compressWriter)(nil)
+var _ http.Hijacker = (*compressWriter)(nil)
+
+// WriteHeader records the status, which is sent once the response has been
+// inspected.
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+
+	// Informational responses are sent immediately and don't end the
+	// headers.
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	cw.status = status
+	cw.wroteHeader = true
+
+	if !cw.compressible() {
+		cw.passthrough()
+	}
+}
+
+// Write buffers p until enough of the body has been written to decide whether
+// the response should be compressed.
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if !cw.decided {
+		cw.buf.Write(p)
+		if cw.buf.Len() >= cw.config.MinSize {
+			if err := cw.decide(true); err != nil {
+				return 0, err
+			}
+		}
+
+		return len(p), nil
+	}
+
+	if cw.writer != nil {
+		return cw.writer.Write(p)
+	}
+
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush compresses the response since its final size can't be known, then
+// flushes any buffered data to the client.
+func (cw *compressWriter) Flush() {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if !cw.decided {
+		if err := cw.decide(true); err != nil {
+			return
+		}
+	}
+
+	if flusher, ok := cw.writer.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack allows upgraded connections to take over the underlying connection.
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := cw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	cw.decided = true
+
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// compressible reports whether the response could be compressed based on its
+// status and headers.
+func (cw *compressWriter) compressible() bool {
+	header := cw.Header()
+
+	switch {
+	case cw.status < 200 || cw.status == http.StatusNoContent || cw.status == http.StatusNotModified:
+		return false
+	case cw.status == http.StatusPartialContent || header.Get("Content-Range") != "":
+		return false
+	case header.Get("Content-Encoding") != "":
+		return false
+	}
+
+	if length, err := strconv.Atoi(header.Get("Content-Length")); err == nil && length < cw.config.MinSize {
+		header.Add("Vary", "Accept-Encoding")
+		return false
+	}
+
+	if contentType := header.Get("Content-Type"); contentType != "" && cw.skipped(contentType) {
+		return false
+	}
+
+	return true
+}
+
+// decide chooses whether to compress the response once the status is known
+// and enough of the body has been written, or the body is complete.
+func (cw *compressWriter) decide(unknownSize bool) error {
+	header := cw.Header()
+
+	if header.Get("Content-Type") == "" && cw.buf.Len() > 0 {
+		header.Set("Content-Type", http.DetectContentType(cw.buf.Bytes()))
+	}
+
+	if cw.skipped(header.Get("Content-Type")) {
+		cw.passthrough()
+		return cw.flushBuffer(cw.ResponseWriter)
+	}
+
+	header.Add("Vary", "Accept-Encoding")
+
+	if !unknownSize && cw.buf.Len() < cw.config.MinSize {
+		cw.passthrough()
+		return cw.flushBuffer(cw.ResponseWriter)
+	}
+
+	header.Set("Content-Encoding", cw.encoder.Encoding)
+	header.Del("Content-Length")
+	header.Del("Accept-Ranges")
+
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+
+	cw.passthrough()
+	cw.writer = cw.encoder.NewWriter(cw.ResponseWriter)
+
+	return cw.flushBuffer(cw.writer)
+}
+
+// passthrough marks the response as decided and sends the status.
+func (cw *compressWriter) passthrough() {
+	cw.decided = true
+	cw.ResponseWriter.WriteHeader(cw.status)
+}
+
+// flushBuffer writes the buffered start of the body to w.
+func (cw *compressWriter) flushBuffer(w io.Writer) error {
+	if cw.buf.Len() == 0 {
+		return nil
+	}
+
+	_, err := w.Write(cw.buf.Bytes())
+	cw.buf.Reset()
+
+	return err
+}
+
+// close finishes the response, deciding whether to compress any buffered
+// body and closing the encoder.
+func (cw *compressWriter) close() {
+	if !cw.wroteHeader {
+		return
+	}
+
+	if !cw.decided {
+		_ = cw.decide(false)
+	}
+
+	if cw.writer != nil {
+		_ = cw.writer.Close()
+	}
+}
+
+// skipped reports whether the content type shouldn't be compressed.
+func (cw *compressWriter) skipped(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+
+	for _, prefix := range cw.config.SkipContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// negotiate returns the encoder that best matches the Accept-Encoding header
+// values, preferring encoders earlier in the list when the client has no
+// preference.
+func negotiate(values []string, encoders []Encoder) (Encoder, bool) {
+	qualities := make(map[string]float64)
+
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			coding, params, _ := strings.Cut(part, ";")
+			coding = strings.ToLower(strings.TrimSpace(coding))
+			q := 1.0
+
+			if key, value, ok := strings.Cut(params, "="); ok && strings.EqualFold(strings.TrimSpace(key), "q") {
+				parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				if err != nil {
+					continue
+				}
+				q = parsed
+			}
+
+			qualities[coding] = q
+		}
+	}
+
+	var best Encoder
+	bestQ := 0.0
+
+	for _, encoder := range encoders {
+		q, ok := qualities[encoder.Encoding]
+		if !ok {
+			q, ok = qualities["*"]
+		}
+
+		if ok && q > bestQ {
+			best, bestQ = encoder, q
+		}
+	}
+
+	return best, bestQ > 0
+}
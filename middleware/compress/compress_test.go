@@ -0,0 +1,164 @@
+package compress
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blakewilliams/medium"
+	"github.com/stretchr/testify/require"
+)
+
+var largeBody = strings.Repeat("hello world ", 200)
+
+func newRouter() *medium.Router[medium.NoData] {
+	router := medium.New(medium.WithNoData)
+	router.Use(Middleware)
+
+	router.Get("/large", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		res := medium.StringResponse(http.StatusOK, largeBody)
+		res.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		res.Header().Set("ETag", `"abc"`)
+
+		return res
+	})
+	router.Get("/small", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		return medium.StringResponse(http.StatusOK, "hello")
+	})
+	router.Get("/image.png", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		res := medium.StringResponse(http.StatusOK, largeBody)
+		res.Header().Set("Content-Type", "image/png")
+
+		return res
+	})
+	router.Get("/encoded", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		res := medium.StringResponse(http.StatusOK, largeBody)
+		res.Header().Set("Content-Encoding", "br")
+
+		return res
+	})
+
+	return router
+}
+
+func gunzip(t *testing.T, body io.Reader) string {
+	t.Helper()
+
+	reader, err := gzip.NewReader(body)
+	require.NoError(t, err)
+
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	return string(decoded)
+}
+
+func TestMiddleware_Gzip(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/large", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	rw := httptest.NewRecorder()
+
+	newRouter().ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "gzip", rw.Header().Get("Content-Encoding"))
+	require.Equal(t, "Accept-Encoding", rw.Header().Get("Vary"))
+	require.Equal(t, `W/"abc"`, rw.Header().Get("ETag"))
+	require.Empty(t, rw.Header().Get("Content-Length"))
+	require.Less(t, rw.Body.Len(), len(largeBody))
+	require.Equal(t, largeBody, gunzip(t, rw.Body))
+}
+
+func TestMiddleware_Deflate(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/large", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0.5, deflate")
+	rw := httptest.NewRecorder()
+
+	newRouter().ServeHTTP(rw, req)
+
+	require.Equal(t, "deflate", rw.Header().Get("Content-Encoding"))
+
+	decoded, err := io.ReadAll(flate.NewReader(rw.Body))
+	require.NoError(t, err)
+	require.Equal(t, largeBody, string(decoded))
+}
+
+func TestMiddleware_Skipped(t *testing.T) {
+	testCases := map[string]struct {
+		path           string
+		acceptEncoding string
+		encoding       string
+		vary           string
+	}{
+		"not accepted":       {path: "/large", acceptEncoding: "", vary: "Accept-Encoding"},
+		"rejected":           {path: "/large", acceptEncoding: "gzip;q=0, deflate;q=0", vary: "Accept-Encoding"},
+		"small body":         {path: "/small", acceptEncoding: "gzip", vary: "Accept-Encoding"},
+		"compressed type":    {path: "/image.png", acceptEncoding: "gzip"},
+		"already compressed": {path: "/encoded", acceptEncoding: "gzip", encoding: "br"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			rw := httptest.NewRecorder()
+
+			newRouter().ServeHTTP(rw, req)
+
+			require.Equal(t, http.StatusOK, rw.Code)
+			require.Equal(t, tc.encoding, rw.Header().Get("Content-Encoding"))
+			require.Equal(t, tc.vary, rw.Header().Get("Vary"))
+			require.NotEqual(t, "gzip", rw.Header().Get("Content-Encoding"))
+		})
+	}
+}
+
+func TestMiddleware_Streaming(t *testing.T) {
+	flushed := make(chan struct{})
+	done := make(chan struct{})
+
+	router := medium.New(medium.WithNoData)
+	router.Use(Middleware)
+	router.Get("/events", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		return medium.EventStream(ctx, r, func(ctx context.Context, events *medium.EventWriter) error {
+			if err := events.Send(medium.Event{Data: "first"}); err != nil {
+				return err
+			}
+			close(flushed)
+			<-done
+
+			return events.Send(medium.Event{Data: "second"})
+		})
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	res, err := http.DefaultTransport.RoundTrip(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, "gzip", res.Header.Get("Content-Encoding"))
+
+	<-flushed
+	reader, err := gzip.NewReader(res.Body)
+	require.NoError(t, err)
+
+	line, err := bufio.NewReader(reader).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "data: first\n", line)
+
+	close(done)
+}
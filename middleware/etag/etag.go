@@ -0,0 +1,161 @@
+// Package etag provides a middleware that adds ETags to responses and answers
+// conditional GET requests with 304 Not Modified.
+package etag
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/blakewilliams/medium"
+)
+
+// Config configures the middleware returned by MiddlewareWithConfig.
+type Config struct {
+	// Weak generates weak ETags instead of strong ETags.
+	Weak bool
+}
+
+// Middleware adds strong ETags to responses. See MiddlewareWithConfig for
+// more information.
+func Middleware(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	defaultMiddleware(rw, r, next)
+}
+
+var defaultMiddleware = MiddlewareWithConfig(Config{})
+
+// MiddlewareWithConfig returns a middleware that buffers successful GET
+// responses and sets an ETag computed from the body, unless the handler
+// already set one, e.g. via medium.WeakETag. Requests with an If-None-Match
+// or If-Modified-Since header matching the response are answered with a 304
+// Not Modified instead of the body.
+//
+// HEAD requests only receive an ETag when set by the handler, since their
+// body is empty. Responses that are flushed, like streaming responses, are
+// sent without an ETag.
+//
+// If-Match preconditions for PUT and PATCH requests must be checked before the
+// resource is modified, so handlers should use medium.CheckPreconditions.
+func MiddlewareWithConfig(config Config) medium.Middleware {
+	return func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next(rw, r)
+			return
+		}
+
+		ew := &etagWriter{ResponseWriter: rw, status: http.StatusOK}
+		next(ew, r)
+
+		if ew.passthrough {
+			return
+		}
+
+		header := rw.Header()
+
+		if ew.status != http.StatusOK {
+			ew.send()
+			return
+		}
+
+		etag := header.Get("ETag")
+		if etag == "" && r.Method == http.MethodGet {
+			etag = medium.BodyETag(ew.buf.Bytes(), config.Weak)
+			header.Set("ETag", etag)
+		}
+
+		var lastModified time.Time
+		if value := header.Get("Last-Modified"); value != "" {
+			lastModified, _ = http.ParseTime(value)
+		}
+
+		res := medium.CheckPreconditions(medium.NewRequest(r, medium.NoData{}, nil), etag, lastModified)
+		if res == nil {
+			ew.send()
+			return
+		}
+
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+		for key, values := range res.Header() {
+			header[key] = values
+		}
+
+		rw.WriteHeader(res.Status())
+	}
+}
+
+// etagWriter buffers the response so the ETag can be computed from the body.
+type etagWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	passthrough bool
+	buf         bytes.Buffer
+}
+
+var _ http.Flusher = (*etagWriter)(nil)
+var _ http.Hijacker = (*etagWriter)(nil)
+
+func (ew *etagWriter) WriteHeader(status int) {
+	if ew.passthrough {
+		ew.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	if ew.wroteHeader || (status >= 100 && status < 200) {
+		return
+	}
+
+	ew.status = status
+	ew.wroteHeader = true
+}
+
+func (ew *etagWriter) Write(p []byte) (int, error) {
+	if ew.passthrough {
+		return ew.ResponseWriter.Write(p)
+	}
+
+	ew.wroteHeader = true
+
+	return ew.buf.Write(p)
+}
+
+// Flush sends the buffered response without an ETag, since the full body
+// isn't known, and flushes it to the client.
+func (ew *etagWriter) Flush() {
+	if !ew.passthrough {
+		ew.send()
+	}
+
+	if flusher, ok := ew.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack allows upgraded connections to take over the underlying connection.
+func (ew *etagWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := ew.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	ew.passthrough = true
+
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (ew *etagWriter) Unwrap() http.ResponseWriter {
+	return ew.ResponseWriter
+}
+
+// send writes the buffered status and body, and passes any further writes
+// through to the client.
+func (ew *etagWriter) send() {
+	ew.passthrough = true
+	ew.ResponseWriter.WriteHeader(ew.status)
+	_, _ = ew.ResponseWriter.Write(ew.buf.Bytes())
+	ew.buf.Reset()
+}
@@ -0,0 +1,118 @@
+package etag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blakewilliams/medium"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouter(middleware medium.Middleware) *medium.Router[medium.NoData] {
+	router := medium.New(medium.WithNoData)
+	router.Use(middleware)
+
+	router.Get("/page", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		return medium.StringResponse(http.StatusOK, "hello")
+	})
+	router.Get("/json", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		res := medium.JSON(http.StatusOK, map[string]int{"id": 1})
+		res.Header().Set("ETag", medium.WeakETag("post-1-v2"))
+		res.Header().Set("Last-Modified", "Wed, 01 Mar 2023 12:00:00 GMT")
+
+		return res
+	})
+	router.Get("/error", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		return medium.StringResponse(http.StatusInternalServerError, "oops")
+	})
+	router.Get("/stream", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		return medium.Stream(ctx, http.StatusOK, func(ctx context.Context, w *medium.StreamWriter) error {
+			_, err := w.WriteString("streamed")
+			return err
+		})
+	})
+
+	return router
+}
+
+func TestMiddleware(t *testing.T) {
+	router := newRouter(Middleware)
+
+	req := httptest.NewRequest(http.MethodGet, "/page", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	etag := rw.Header().Get("ETag")
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, medium.BodyETag([]byte("hello"), false), etag)
+	require.Equal(t, "hello", rw.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/page", nil)
+	req.Header.Set("If-None-Match", etag)
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusNotModified, rw.Code)
+	require.Equal(t, etag, rw.Header().Get("ETag"))
+	require.Empty(t, rw.Header().Get("Content-Type"))
+	require.Empty(t, rw.Body.String())
+}
+
+func TestMiddleware_Weak(t *testing.T) {
+	router := newRouter(MiddlewareWithConfig(Config{Weak: true}))
+
+	req := httptest.NewRequest(http.MethodGet, "/page", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, medium.BodyETag([]byte("hello"), true), rw.Header().Get("ETag"))
+}
+
+func TestMiddleware_HandlerVersion(t *testing.T) {
+	router := newRouter(Middleware)
+
+	req := httptest.NewRequest(http.MethodGet, "/json", nil)
+	req.Header.Set("If-None-Match", `W/"post-1-v2"`)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusNotModified, rw.Code)
+	require.Equal(t, `W/"post-1-v2"`, rw.Header().Get("ETag"))
+
+	req = httptest.NewRequest(http.MethodHead, "/json", nil)
+	req.Header.Set("If-Modified-Since", "Wed, 01 Mar 2023 12:00:00 GMT")
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusNotModified, rw.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/json", nil)
+	req.Header.Set("If-None-Match", `W/"post-1-v1"`)
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "{\"id\":1}\n", rw.Body.String())
+}
+
+func TestMiddleware_Skipped(t *testing.T) {
+	router := newRouter(Middleware)
+
+	req := httptest.NewRequest(http.MethodGet, "/error", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusInternalServerError, rw.Code)
+	require.Empty(t, rw.Header().Get("ETag"))
+	require.Equal(t, "oops", rw.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/stream", nil)
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Empty(t, rw.Header().Get("ETag"))
+	require.Equal(t, "streamed", rw.Body.String())
+}
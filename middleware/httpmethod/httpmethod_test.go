@@ -35,5 +35,5 @@ func Test_RewritePost(t *testing.T) {
 
 	r.ServeHTTP(res, req)
 
-	require.Equal(t, http.StatusNotFound, res.Result().StatusCode)
+	require.Equal(t, http.StatusMethodNotAllowed, res.Result().StatusCode)
 }
@@ -0,0 +1,349 @@
+package rescue
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/blakewilliams/medium"
+	"github.com/blakewilliams/medium/mlog"
+)
+
+// EnvironmentDevelopment is the environment that ErrorPage renders the
+// developer error page in.
+const EnvironmentDevelopment = "development"
+
+// DefaultSourceContext is the number of lines shown before and after each
+// line of the stack trace on the developer error page.
+const DefaultSourceContext = 5
+
+// redacted replaces the values of sensitive headers and cookies on the
+// developer error page.
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders are the headers whose values are redacted on the developer
+// error page, since it's often enabled in shared environments.
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+
+// maxSourceFrames is the number of stack frames that are annotated with their
+// source on the developer error page.
+const maxSourceFrames = 20
+
+// Config configures the ErrorHandler returned by ErrorPage.
+type Config struct {
+	// Environment selects which page is rendered. The developer error page is
+	// rendered when it's EnvironmentDevelopment, otherwise the production
+	// page is rendered.
+	Environment string
+	// Logs provides the recent log lines shown on the developer error page.
+	Logs *mlog.Recorder
+	// SourceContext is the number of lines shown before and after each line
+	// of the stack trace. Defaults to DefaultSourceContext.
+	SourceContext int
+	// ProductionPage renders the production error page, which must not
+	// expose details of the error. The request ID is also set in the
+	// X-Request-ID response header and logged so the error can be found.
+	// Defaults to a generic 500 Internal Server Error page.
+	ProductionPage func(rw http.ResponseWriter, r *http.Request, requestID string)
+}
+
+// ErrorPage returns an ErrorHandler that renders an HTML error page for
+// rescued panics, for use with Middleware.
+//
+// In development the page shows the panic value, the stack trace annotated
+// with source code, the request's headers, params, form data and cookies, the
+// matched route, and recent logs. The values of cookies and the
+// Authorization, Cookie and Proxy-Authorization headers are redacted. In other environments a page that only
+// shows a request ID is rendered, so that details of the error aren't exposed.
+//
+//	router.Use(rescue.Middleware(rescue.ErrorPage(rescue.Config{
+//		Environment: os.Getenv("APP_ENV"),
+//		Logs:        recorder,
+//	})))
+func ErrorPage(config Config) ErrorHandler {
+	if config.SourceContext == 0 {
+		config.SourceContext = DefaultSourceContext
+	}
+
+	if config.ProductionPage == nil {
+		config.ProductionPage = defaultProductionPage
+	}
+
+	return func(rw http.ResponseWriter, r *http.Request, err error) {
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+
+		rw.Header().Set("X-Request-ID", requestID)
+		mlog.Error(r.Context(), "rendering error page", mlog.Fields{"request_id": requestID, "error": err.Error()})
+
+		if config.Environment != EnvironmentDevelopment {
+			config.ProductionPage(rw, r, requestID)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+		rw.WriteHeader(http.StatusInternalServerError)
+
+		if err := developmentPage.Execute(rw, newDevelopmentPageData(config, r, err, requestID)); err != nil {
+			mlog.Error(r.Context(), "error rendering development error page", mlog.Fields{"error": err.Error()})
+		}
+	}
+}
+
+func newRequestID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+func defaultProductionPage(rw http.ResponseWriter, r *http.Request, requestID string) {
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	rw.WriteHeader(http.StatusInternalServerError)
+
+	_ = productionPage.Execute(rw, requestID)
+}
+
+var productionPage = template.Must(template.New("production").Parse(`<!DOCTYPE html>
+<html>
+<head><title>500 Internal Server Error</title></head>
+<body>
+<h1>Something went wrong</h1>
+<p>We've been notified and are looking into it.</p>
+<p>Request ID: <code>{{.}}</code></p>
+</body>
+</html>
+`))
+
+// developmentPageData is the data passed to the developer error page.
+type developmentPageData struct {
+	Error     string
+	Type      string
+	RequestID string
+	Frames    []frame
+	Request   *http.Request
+	Headers   http.Header
+	Form      url.Values
+	Cookies   []*http.Cookie
+	Route     *medium.RouteData
+	Logs      []string
+}
+
+// frame is a stack frame annotated with the source surrounding it.
+type frame struct {
+	Function string
+	File     string
+	Line     int
+	Source   []sourceLine
+}
+
+type sourceLine struct {
+	Number  int
+	Text    string
+	Current bool
+}
+
+func newDevelopmentPageData(config Config, r *http.Request, err error, requestID string) developmentPageData {
+	data := developmentPageData{
+		Error:     err.Error(),
+		Type:      fmt.Sprintf("%T", err),
+		RequestID: requestID,
+		Request:   r,
+		Headers:   redactHeaders(r.Header),
+		Cookies:   redactCookies(r.Cookies()),
+		// The body may have been read by the handler, so only form data that
+		// was already parsed is shown.
+		Form: r.Form,
+	}
+
+	if data.Form == nil {
+		data.Form = r.URL.Query()
+	}
+
+	if route, ok := medium.RouteDataFrom(r.Context()); ok {
+		data.Route = route
+	}
+
+	if config.Logs != nil {
+		data.Logs = config.Logs.Lines()
+	}
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		return data
+	}
+
+	data.Type = fmt.Sprintf("%T", panicErr.Value)
+	sources := make(map[string][]string)
+
+	for i, runtimeFrame := range panicErr.Frames() {
+		f := frame{Function: runtimeFrame.Function, File: runtimeFrame.File, Line: runtimeFrame.Line}
+		if i < maxSourceFrames {
+			f.Source = sourceFor(sources, f.File, f.Line, config.SourceContext)
+		}
+
+		data.Frames = append(data.Frames, f)
+	}
+
+	return data
+}
+
+// redactHeaders returns a copy of header with the values of sensitiveHeaders
+// redacted.
+func redactHeaders(header http.Header) http.Header {
+	header = header.Clone()
+
+	for _, name := range sensitiveHeaders {
+		if _, ok := header[name]; ok {
+			header[name] = []string{redacted}
+		}
+	}
+
+	return header
+}
+
+// redactCookies returns copies of cookies with their values redacted.
+func redactCookies(cookies []*http.Cookie) []*http.Cookie {
+	redactedCookies := make([]*http.Cookie, len(cookies))
+	for i, cookie := range cookies {
+		redactedCookies[i] = &http.Cookie{Name: cookie.Name, Value: redacted}
+	}
+
+	return redactedCookies
+}
+
+// sourceFor returns the lines of file surrounding line, caching the contents
+// of each file in sources. nil is returned if the file can't be read.
+func sourceFor(sources map[string][]string, file string, line int, context int) []sourceLine {
+	lines, ok := sources[file]
+	if !ok {
+		contents, err := os.ReadFile(file)
+		if err == nil {
+			lines = strings.Split(string(contents), "\n")
+		}
+
+		sources[file] = lines
+	}
+
+	if line < 1 || line > len(lines) {
+		return nil
+	}
+
+	start := line - context
+	if start < 1 {
+		start = 1
+	}
+
+	end := line + context
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	source := make([]sourceLine, 0, end-start+1)
+	for number := start; number <= end; number++ {
+		source = append(source, sourceLine{Number: number, Text: lines[number-1], Current: number == line})
+	}
+
+	return source
+}
+
+var developmentPage = template.Must(template.New("development").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>{{.Error}}</title>
+<style>
+body { font-family: -apple-system, sans-serif; margin: 0; color: #222; }
+header { background: #b91c1c; color: #fff; padding: 1.5rem 2rem; }
+header h1 { margin: 0 0 .5rem; font-size: 1.5rem; word-break: break-word; }
+main { padding: 0 2rem 2rem; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: .25rem; margin-top: 2rem; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; vertical-align: top; padding: .25rem .5rem; border-bottom: 1px solid #eee; font-family: monospace; }
+th { width: 20%; }
+pre { margin: 0; background: #f6f6f6; overflow-x: auto; }
+.frame { margin-bottom: 1rem; }
+.frame summary { font-family: monospace; cursor: pointer; }
+.line { display: block; padding: 0 .5rem; }
+.line.current { background: #fde2e2; }
+.number { display: inline-block; width: 4em; color: #888; }
+.logs { background: #222; color: #eee; padding: .5rem; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Error}}</h1>
+<div>{{.Type}} &middot; {{.Request.Method}} {{.Request.URL}} &middot; Request ID {{.RequestID}}</div>
+</header>
+<main>
+<h2>Stack trace</h2>
+{{range $i, $frame := .Frames}}
+<details class="frame"{{if eq $i 0}} open{{end}}>
+<summary>{{$frame.Function}}<br>{{$frame.File}}:{{$frame.Line}}</summary>
+{{if $frame.Source}}<pre>{{range $frame.Source}}<span class="line{{if .Current}} current{{end}}"><span class="number">{{.Number}}</span>{{.Text}}</span>{{end}}</pre>{{end}}
+</details>
+{{else}}
+<p>No stack trace available.</p>
+{{end}}
+
+<h2>Route</h2>
+{{with .Route}}
+<table>
+<tr><th>Path</th><td>{{.HandlerPath}}</td></tr>
+{{range $name, $value := .Params}}<tr><th>Param {{$name}}</th><td>{{$value}}</td></tr>{{end}}
+{{range $key, $value := .Metadata}}<tr><th>Metadata {{$key}}</th><td>{{$value}}</td></tr>{{end}}
+</table>
+{{else}}
+<p>No route matched.</p>
+{{end}}
+
+<h2>Request</h2>
+<table>
+<tr><th>Method</th><td>{{.Request.Method}}</td></tr>
+<tr><th>URL</th><td>{{.Request.URL}}</td></tr>
+<tr><th>Protocol</th><td>{{.Request.Proto}}</td></tr>
+<tr><th>Host</th><td>{{.Request.Host}}</td></tr>
+<tr><th>Remote address</th><td>{{.Request.RemoteAddr}}</td></tr>
+</table>
+
+<h2>Headers</h2>
+<table>
+{{range $name, $values := .Headers}}{{range $values}}<tr><th>{{$name}}</th><td>{{.}}</td></tr>{{end}}{{end}}
+</table>
+
+<h2>Form data</h2>
+{{if .Form}}
+<table>
+{{range $name, $values := .Form}}{{range $values}}<tr><th>{{$name}}</th><td>{{.}}</td></tr>{{end}}{{end}}
+</table>
+{{else}}
+<p>No form data.</p>
+{{end}}
+
+<h2>Cookies</h2>
+{{if .Cookies}}
+<table>
+{{range .Cookies}}<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>{{end}}
+</table>
+{{else}}
+<p>No cookies.</p>
+{{end}}
+
+<h2>Recent logs</h2>
+{{if .Logs}}
+<pre class="logs">{{range .Logs}}{{.}}
+{{end}}</pre>
+{{else}}
+<p>No logs recorded.</p>
+{{end}}
+</main>
+</body>
+</html>
+`))
@@ -0,0 +1,162 @@
+package rescue
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blakewilliams/medium"
+	"github.com/blakewilliams/medium/mlog"
+	"github.com/stretchr/testify/require"
+)
+
+func newErrorPageRouter(config Config) *medium.Router[medium.NoData] {
+	logger := mlog.New(io.Discard, mlog.LevelDebug, mlog.JSONFormatter{})
+	if config.Logs != nil {
+		logger = mlog.New(config.Logs, mlog.LevelDebug, mlog.JSONFormatter{})
+	}
+
+	router := medium.New(medium.WithNoData)
+	router.Use(func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		next(rw, r.WithContext(mlog.Inject(r.Context(), logger)))
+	})
+	router.Use(Middleware(ErrorPage(config)))
+
+	router.Post("/users/:id", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		_, _ = r.FormData()
+		mlog.Info(ctx, "loading user", mlog.Fields{"id": r.Param("id")})
+
+		panic("the truth is out there")
+	}, medium.WithMetadata("permission", "users:read"))
+
+	return router
+}
+
+func newErrorPageRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/users/42?tab=profile", strings.NewReader("name=<b>Fox</b>"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "spooky"})
+	req.Header.Set("Authorization", "Bearer trustno1")
+	req.Header.Set("Proxy-Authorization", "Basic trustno1")
+
+	return req
+}
+
+func TestErrorPage_Development(t *testing.T) {
+	router := newErrorPageRouter(Config{Environment: EnvironmentDevelopment, Logs: mlog.NewRecorder(10)})
+
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, newErrorPageRequest())
+
+	body := rw.Body.String()
+
+	require.Equal(t, http.StatusInternalServerError, rw.Code)
+	require.Equal(t, "text/html; charset=utf-8", rw.Header().Get("Content-Type"))
+	require.NotEmpty(t, rw.Header().Get("X-Request-ID"))
+
+	// Panic value and stack trace annotated with the panicking line.
+	require.Contains(t, body, "Panic rescued: the truth is out there")
+	require.Contains(t, body, "error_page_test.go")
+	require.Contains(t, body, `panic(&#34;the truth is out there&#34;)`)
+
+	// Request details, escaped.
+	require.Contains(t, body, "/users/42?tab=profile")
+	require.Contains(t, body, "&lt;b&gt;Fox&lt;/b&gt;")
+	require.NotContains(t, body, "<b>Fox</b>")
+	require.Contains(t, body, "application/x-www-form-urlencoded")
+
+	// Sensitive headers and cookies, redacted.
+	require.Contains(t, body, "<th>Authorization</th><td>[REDACTED]</td>")
+	require.Contains(t, body, "<th>Proxy-Authorization</th><td>[REDACTED]</td>")
+	require.Contains(t, body, "<th>Cookie</th><td>[REDACTED]</td>")
+	require.Contains(t, body, "<th>session</th><td>[REDACTED]</td>")
+	require.NotContains(t, body, "trustno1")
+	require.NotContains(t, body, "spooky")
+
+	// Matched route.
+	require.Contains(t, body, "/users/:id")
+	require.Contains(t, body, "Param id")
+	require.Contains(t, body, "users:read")
+
+	// Recent logs.
+	require.Contains(t, body, "loading user")
+}
+
+func TestErrorPage_Production(t *testing.T) {
+	router := newErrorPageRouter(Config{Environment: "production", Logs: mlog.NewRecorder(10)})
+
+	req := newErrorPageRequest()
+	req.Header.Set("X-Request-ID", "abc123")
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	body := rw.Body.String()
+
+	require.Equal(t, http.StatusInternalServerError, rw.Code)
+	require.Equal(t, "abc123", rw.Header().Get("X-Request-ID"))
+	require.Contains(t, body, "Request ID: <code>abc123</code>")
+	require.NotContains(t, body, "the truth is out there")
+	require.NotContains(t, body, "spooky")
+}
+
+func TestErrorPage_CustomProductionPage(t *testing.T) {
+	router := newErrorPageRouter(Config{
+		ProductionPage: func(rw http.ResponseWriter, r *http.Request, requestID string) {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = rw.Write([]byte("custom " + requestID))
+		},
+	})
+
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, newErrorPageRequest())
+
+	require.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	require.Equal(t, "custom "+rw.Header().Get("X-Request-ID"), rw.Body.String())
+}
+
+func TestMiddleware_PanicError(t *testing.T) {
+	errSpooky := errors.New("spooky")
+
+	var rescued error
+	router := medium.New(medium.WithNoData)
+	router.Use(Middleware(func(rw http.ResponseWriter, r *http.Request, err error) {
+		rescued = err
+	}))
+	router.Get("/", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		panic(errSpooky)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.ErrorIs(t, rescued, errSpooky)
+	require.Equal(t, "spooky", rescued.Error())
+
+	var panicErr *PanicError
+	require.ErrorAs(t, rescued, &panicErr)
+	require.Contains(t, panicErr.Frames()[0].Function, "TestMiddleware_PanicError")
+}
+
+func TestMiddleware_PanicWithTimeout(t *testing.T) {
+	var rescued error
+	router := medium.New(medium.WithNoData)
+	router.Use(Middleware(func(rw http.ResponseWriter, r *http.Request, err error) {
+		rescued = err
+	}))
+	router.Timeout(time.Second)
+	router.Get("/", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		panic("the truth is out there")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var panicErr *PanicError
+	require.ErrorAs(t, rescued, &panicErr)
+	require.Equal(t, "the truth is out there", panicErr.Value)
+	require.Equal(t, "Panic rescued: the truth is out there", panicErr.Error())
+	require.Contains(t, panicErr.Frames()[0].Function, "TestMiddleware_PanicWithTimeout")
+}
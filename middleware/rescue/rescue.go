@@ -3,6 +3,8 @@ package rescue
 import (
 	"fmt"
 	"net/http"
+	"runtime"
+	"strings"
 
 	"github.com/blakewilliams/medium"
 	"github.com/blakewilliams/medium/mlog"
@@ -17,21 +19,79 @@ type Logger interface {
 // An ErrorHandler is a function that is called when an error occurs.
 type ErrorHandler func(http.ResponseWriter, *http.Request, error)
 
+// PanicError is passed to the ErrorHandler when a panic is rescued. It holds
+// the value passed to panic and the stack of the panicking goroutine.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	pcs   []uintptr
+}
+
+func (e *PanicError) Error() string {
+	if err, ok := e.Value.(error); ok {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("Panic rescued: %v", e.Value)
+}
+
+// Unwrap returns the value passed to panic if it's an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Frames returns the stack frames of the panicking goroutine, starting with
+// the function that called panic.
+func (e *PanicError) Frames() []runtime.Frame {
+	frames := make([]runtime.Frame, 0, len(e.pcs))
+	callers := runtime.CallersFrames(e.pcs)
+
+	for {
+		frame, more := callers.Next()
+
+		// Skip the runtime's panic handling frames.
+		if len(frames) > 0 || !strings.HasPrefix(frame.Function, "runtime.") {
+			frames = append(frames, frame)
+		}
+
+		if !more {
+			return frames
+		}
+	}
+}
+
 // Middleware accepts an ErrorHandler and returns a medium.Middleware that will
-// rescue errors that happen in middlewares that are called after it.
+// rescue errors that happen in middlewares that are called after it. The
+// handler is passed a *PanicError, which unwraps to the value passed to panic
+// if it's an error.
 func Middleware(handler ErrorHandler) medium.Middleware {
 	return func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 		defer func() {
 			rec := recover()
 			if rec != nil {
+				var pcs []uintptr
+
+				// Panics re-raised from another goroutine, e.g. by
+				// Router.Timeout, carry the stack of the goroutine that
+				// panicked.
+				if p, ok := rec.(*medium.Panic); ok {
+					rec = p.Value
+					pcs = p.PCs
+				} else {
+					pcs = make([]uintptr, 64)
+					// Skip runtime.Callers and this function.
+					pcs = pcs[:runtime.Callers(2, pcs)]
+				}
+
 				switch err := rec.(type) {
 				case error:
 					mlog.Error(r.Context(), "rescued error in middleware", mlog.Fields{"error": err})
-					handler(rw, r, err)
 				default:
 					mlog.Error(r.Context(), "rescued non-error in middleware", mlog.Fields{"err": fmt.Sprintf("%v", err)})
-					handler(rw, r, fmt.Errorf("Panic rescued: %v", err))
 				}
+
+				handler(rw, r, &PanicError{Value: rec, pcs: pcs})
 			}
 		}()
 
@@ -0,0 +1,164 @@
+// Package timeout provides a middleware that limits how long the rest of the
+// middleware chain and the router have to respond to a request.
+package timeout
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/blakewilliams/medium"
+	"github.com/blakewilliams/medium/mlog"
+)
+
+// Config configures the middleware returned by MiddlewareWithConfig.
+type Config struct {
+	// Timeout is how long the request has to be responded to.
+	Timeout time.Duration
+	// Response returns the response sent when the timeout elapses. Defaults
+	// to a 503 Service Unavailable.
+	Response func(r *http.Request) medium.Response
+}
+
+// Middleware responds with a 503 Service Unavailable to requests that aren't
+// responded to before timeout. See MiddlewareWithConfig for more
+// information.
+func Middleware(timeout time.Duration) medium.Middleware {
+	return MiddlewareWithConfig(Config{Timeout: timeout})
+}
+
+// MiddlewareWithConfig returns a middleware that calls next with a context
+// that is canceled once the timeout elapses. The response written by next is
+// buffered and sent when next returns, or discarded in favor of the timeout
+// response if the timeout elapses first. Writes after the timeout return
+// http.ErrHandlerTimeout.
+//
+// Panics in next are re-raised as a *medium.Panic holding the stack of the
+// goroutine that panicked.
+//
+// Since responses are buffered, flushing and hijacking aren't supported. Use
+// Router.Timeout to limit how long route handlers have to return a response
+// without buffering.
+func MiddlewareWithConfig(config Config) medium.Middleware {
+	if config.Response == nil {
+		config.Response = func(r *http.Request) medium.Response {
+			return medium.StringResponse(http.StatusServiceUnavailable, "503 service unavailable")
+		}
+	}
+
+	return func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		ctx, cancel := context.WithTimeout(r.Context(), config.Timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		tw := &timeoutWriter{header: make(http.Header)}
+		done := make(chan struct{})
+		panics := make(chan any, 1)
+
+		go func() {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					panics <- withStack(recovered)
+				}
+			}()
+
+			next(tw, r)
+			close(done)
+		}()
+
+		select {
+		case recovered := <-panics:
+			panic(recovered)
+		case <-done:
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+
+			tw.send(rw)
+		case <-ctx.Done():
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+
+			tw.timedOut = true
+
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return
+			}
+
+			mlog.Warn(r.Context(), "request timed out", mlog.Fields{"path": r.URL.Path, "timeout": config.Timeout.String()})
+			medium.WriteResponse(rw, r, config.Response(r))
+		}
+	}
+}
+
+// withStack returns recovered as a *medium.Panic holding the stack of the
+// goroutine that panicked, so it isn't lost when the panic is re-raised. It
+// must be called directly from the deferred function that recovered the
+// panic. Values that are already a *medium.Panic and http.ErrAbortHandler are
+// returned as is.
+func withStack(recovered any) any {
+	if _, ok := recovered.(*medium.Panic); ok || recovered == http.ErrAbortHandler {
+		return recovered
+	}
+
+	pcs := make([]uintptr, 64)
+	// Skip runtime.Callers, withStack and the deferred function.
+	pcs = pcs[:runtime.Callers(3, pcs)]
+
+	return &medium.Panic{Value: recovered, PCs: pcs}
+}
+
+// timeoutWriter buffers the response until the handler returns, and rejects
+// writes after the timeout.
+type timeoutWriter struct {
+	mu          sync.Mutex
+	header      http.Header
+	buf         bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header { return tw.header }
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+
+	tw.status = status
+	tw.wroteHeader = true
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	tw.wroteHeader = true
+
+	return tw.buf.Write(p)
+}
+
+// send writes the buffered response to rw.
+func (tw *timeoutWriter) send(rw http.ResponseWriter) {
+	for key, values := range tw.header {
+		rw.Header()[key] = values
+	}
+
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+
+	rw.WriteHeader(tw.status)
+	_, _ = rw.Write(tw.buf.Bytes())
+}
@@ -0,0 +1,101 @@
+package timeout
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blakewilliams/medium"
+	"github.com/blakewilliams/medium/middleware/rescue"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	writeErrs := make(chan error, 1)
+
+	router := medium.New(medium.WithNoData)
+	router.Use(Middleware(20 * time.Millisecond))
+	router.Use(func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		if r.URL.Path != "/slow" {
+			next(rw, r)
+			return
+		}
+
+		<-r.Context().Done()
+		time.Sleep(10 * time.Millisecond)
+
+		_, err := rw.Write([]byte("too late"))
+		writeErrs <- err
+	})
+	router.Get("/fast", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		res := medium.StringResponse(http.StatusCreated, "fast")
+		res.Header().Set("X-Fast", "true")
+
+		return res
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/fast", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusCreated, rw.Code)
+	require.Equal(t, "fast", rw.Body.String())
+	require.Equal(t, "true", rw.Header().Get("X-Fast"))
+
+	req = httptest.NewRequest(http.MethodGet, "/slow", nil)
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	require.Equal(t, "503 service unavailable", rw.Body.String())
+
+	select {
+	case err := <-writeErrs:
+		require.True(t, errors.Is(err, http.ErrHandlerTimeout))
+	case <-time.After(time.Second):
+		t.Fatal("handler did not write after the timeout")
+	}
+}
+
+func TestMiddlewareWithConfig_Response(t *testing.T) {
+	router := medium.New(medium.WithNoData)
+	router.Use(MiddlewareWithConfig(Config{
+		Timeout: 10 * time.Millisecond,
+		Response: func(r *http.Request) medium.Response {
+			return medium.StringResponse(http.StatusGatewayTimeout, "gateway timeout")
+		},
+	}))
+	router.Get("/", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		<-ctx.Done()
+		return medium.OK()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusGatewayTimeout, rw.Code)
+	require.Equal(t, "gateway timeout", rw.Body.String())
+}
+
+func TestMiddleware_Panic(t *testing.T) {
+	var rescued error
+	router := medium.New(medium.WithNoData)
+	router.Use(rescue.Middleware(func(rw http.ResponseWriter, r *http.Request, err error) {
+		rescued = err
+	}))
+	router.Use(Middleware(time.Second))
+	router.Get("/", func(ctx context.Context, r *medium.Request[medium.NoData]) medium.Response {
+		panic("oh no")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var panicErr *rescue.PanicError
+	require.ErrorAs(t, rescued, &panicErr)
+	require.Equal(t, "oh no", panicErr.Value)
+	require.Contains(t, panicErr.Frames()[0].Function, "TestMiddleware_Panic")
+}
@@ -0,0 +1,69 @@
+package mlog
+
+import (
+	"bytes"
+	"sync"
+)
+
+// Recorder is an io.Writer that keeps the most recent lines written to it.
+// It can be combined with other writers via io.MultiWriter to keep recent
+// logs in memory, e.g. to display them on development error pages.
+type Recorder struct {
+	mu      sync.Mutex
+	lines   []string
+	next    int
+	full    bool
+	partial []byte
+}
+
+// NewRecorder returns a Recorder that keeps the last size lines.
+func NewRecorder(size int) *Recorder {
+	if size < 1 {
+		size = 1
+	}
+
+	return &Recorder{lines: make([]string, size)}
+}
+
+// Write records each complete line in p. Incomplete lines are buffered until
+// the rest of the line is written.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data := p
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i == -1 {
+			r.partial = append(r.partial, data...)
+			break
+		}
+
+		r.record(string(append(r.partial, data[:i]...)))
+		r.partial = r.partial[:0]
+		data = data[i+1:]
+	}
+
+	return len(p), nil
+}
+
+func (r *Recorder) record(line string) {
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Lines returns the recorded lines, oldest first.
+func (r *Recorder) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]string(nil), r.lines[:r.next]...)
+	}
+
+	return append(append([]string(nil), r.lines[r.next:]...), r.lines[:r.next]...)
+}
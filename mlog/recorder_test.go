@@ -0,0 +1,38 @@
+package mlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder(2)
+	logger := New(recorder, LevelDebug, PrettyFormatter{})
+
+	require.Empty(t, recorder.Lines())
+
+	logger.Info("one", Fields{})
+	require.Len(t, recorder.Lines(), 1)
+	require.Contains(t, recorder.Lines()[0], "one")
+
+	logger.Info("two", Fields{})
+	logger.Info("three", Fields{})
+
+	lines := recorder.Lines()
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], "two")
+	require.Contains(t, lines[1], "three")
+}
+
+func TestRecorder_PartialLines(t *testing.T) {
+	recorder := NewRecorder(5)
+
+	_, _ = recorder.Write([]byte("hello "))
+	require.Empty(t, recorder.Lines())
+
+	_, _ = recorder.Write([]byte("world\nfoo\nba"))
+	_, _ = recorder.Write([]byte("r\n"))
+
+	require.Equal(t, []string{"hello world", "foo", "bar"}, recorder.Lines())
+}
@@ -0,0 +1,87 @@
+package medium
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// anyMethod is used as the method of routes that respond to every method,
+// such as those defined by Mount.
+const anyMethod = "*"
+
+// writerResponse is implemented by responses that write directly to the
+// http.ResponseWriter instead of providing a Body. Headers set on the
+// response are written before writeResponse is called.
+type writerResponse interface {
+	Response
+	writeResponse(rw http.ResponseWriter, r *http.Request)
+}
+
+// mountResponse is returned by mounted routes and calls the mounted handler
+// when the response is written.
+type mountResponse struct {
+	handler http.Handler
+	req     *http.Request
+	header  http.Header
+}
+
+var _ writerResponse = (*mountResponse)(nil)
+
+func (mr *mountResponse) Status() int         { return 0 }
+func (mr *mountResponse) Header() http.Header { return mr.header }
+func (mr *mountResponse) Body() io.Reader     { return nil }
+
+func (mr *mountResponse) writeResponse(rw http.ResponseWriter, _ *http.Request) {
+	mr.handler.ServeHTTP(rw, mr.req)
+}
+
+// Mount routes every request with the given prefix, regardless of method, to
+// handler. The prefix is stripped from the request path before handler is
+// called, so a handler mounted at `/debug` will receive `/debug/pprof` as
+// `/pprof`.
+//
+// The group's data creator and BeforeFuncs are called before handler, so
+// BeforeFuncs can be used for authentication or to modify the context passed
+// to handler. Since handler writes directly to the http.ResponseWriter, the
+// Response returned by next can't be used to inspect handler's output.
+func (g *RouteGroup[ParentData, Data]) Mount(prefix string, handler http.Handler, opts ...RouteOption) {
+	mounted := func(ctx context.Context, req *Request[Data]) Response {
+		r := req.Request().WithContext(ctx)
+		r.URL = new(url.URL)
+		*r.URL = *req.URL()
+		r.URL.Path = "/" + req.Params()["*"]
+		r.URL.RawPath = stripRawPath(req.URL().RawPath, r.URL.Path)
+
+		return &mountResponse{handler: handler, req: r, header: http.Header{}}
+	}
+
+	g.Match(anyMethod, prefix, mounted, opts...)
+	// Names must be unique, so only the prefix route is named.
+	g.Match(anyMethod, joinPath(prefix, "*"), mounted, append(opts[:len(opts):len(opts)], WithName(""))...)
+}
+
+// stripRawPath returns the suffix of rawPath that decodes to path, so that
+// escaped characters like %2F are preserved in the path passed to mounted
+// handlers. An empty string is returned if rawPath is empty or has no such
+// suffix.
+func stripRawPath(rawPath string, path string) string {
+	for i := 0; i < len(rawPath); i++ {
+		if rawPath[i] != '/' {
+			continue
+		}
+
+		if unescaped, err := url.PathUnescape(rawPath[i:]); err == nil && unescaped == path {
+			return rawPath[i:]
+		}
+	}
+
+	return ""
+}
+
+// Mount routes every request with the given prefix, regardless of method, to
+// handler. See RouteGroup.Mount for more information.
+func (r *Router[T]) Mount(prefix string, handler http.Handler, opts ...RouteOption) {
+	r.routeGroup.Mount(prefix, handler, opts...)
+}
@@ -0,0 +1,143 @@
+package medium
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_Mount(t *testing.T) {
+	router := New(WithNoData)
+
+	router.Mount("/debug", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusAccepted)
+		_, _ = rw.Write([]byte(r.Method + " " + r.URL.Path))
+	}))
+
+	testCases := map[string]struct {
+		method string
+		path   string
+		body   string
+	}{
+		"prefix":         {method: http.MethodGet, path: "/debug", body: "GET /"},
+		"trailing slash": {method: http.MethodGet, path: "/debug/", body: "GET /"},
+		"nested path":    {method: http.MethodPost, path: "/debug/pprof/heap", body: "POST /pprof/heap"},
+		"escaped path":   {method: http.MethodGet, path: "/debug/a%20b", body: "GET /a b"},
+		"any method":     {method: "PROPFIND", path: "/debug/files", body: "PROPFIND /files"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			rw := httptest.NewRecorder()
+
+			router.ServeHTTP(rw, req)
+
+			require.Equal(t, http.StatusAccepted, rw.Code)
+			require.Equal(t, tc.body, rw.Body.String())
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/debugger", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func TestRouter_Mount_EncodedPath(t *testing.T) {
+	router := New(WithNoData)
+
+	teams := SubRouter(router, "/teams/:team", WithNoData)
+	teams.Mount("/files", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, _ = rw.Write([]byte(r.URL.Path + " " + r.URL.EscapedPath()))
+	}))
+
+	testCases := map[string]struct {
+		path string
+		body string
+	}{
+		"encoded slash":    {path: "/teams/acme/files/docs/a%2Fb.txt", body: "/docs/a/b.txt /docs/a%2Fb.txt"},
+		"encoded prefix":   {path: "/teams/ac%6De/files/a%2Fb.txt", body: "/a/b.txt /a%2Fb.txt"},
+		"no encoded slash": {path: "/teams/acme/files/notes.txt", body: "/notes.txt /notes.txt"},
+		"prefix":           {path: "/teams/acme/files", body: "/ /"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			rw := httptest.NewRecorder()
+
+			router.ServeHTTP(rw, req)
+
+			require.Equal(t, tc.body, rw.Body.String())
+		})
+	}
+}
+
+func TestGroup_Mount(t *testing.T) {
+	type ctxKey struct{}
+
+	router := New(WithNoData)
+
+	admin := New(WithNoData)
+	admin.Get("/users/:id", func(ctx context.Context, r *Request[NoData]) Response {
+		return StringResponse(http.StatusOK, fmt.Sprintf("user %s for %s", r.Params()["id"], r.Request().Context().Value(ctxKey{})))
+	})
+
+	teams := SubRouter(router, "/teams/:teamID", WithNoData)
+	teams.Before(func(ctx context.Context, r *Request[NoData], next Next) Response {
+		if r.Header().Get("Authorization") == "" {
+			return StringResponse(http.StatusForbidden, "forbidden")
+		}
+
+		res := next(context.WithValue(ctx, ctxKey{}, "team "+r.Params()["teamID"]))
+		res.Header().Set("x-from-before", "true")
+
+		return res
+	})
+	teams.Mount("/admin", admin)
+
+	req := httptest.NewRequest(http.MethodGet, "/teams/1/admin/users/2", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusForbidden, rw.Code)
+
+	req.Header.Set("Authorization", "secret")
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "user 2 for team 1", rw.Body.String())
+	require.Equal(t, "true", rw.Header().Get("x-from-before"))
+}
+
+func TestRouter_Mount_RouteOptions(t *testing.T) {
+	router := New(WithNoData)
+
+	router.Mount("/debug", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}), WithName("debug"), WithMiddleware(func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		rw.Header().Set("X-Debug", "true")
+		next(rw, r)
+	}))
+
+	for _, path := range []string{"/debug", "/debug/pprof"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rw := httptest.NewRecorder()
+
+		router.ServeHTTP(rw, req)
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, "true", rw.Header().Get("X-Debug"))
+	}
+
+	path, err := router.URLFor("debug", nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "/debug", path)
+}
@@ -0,0 +1,152 @@
+package medium
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// DefaultMaxMultipartMemory is the number of bytes of a multipart form that
+// are stored in memory by File and Files before file parts are written to
+// temporary files on disk.
+const DefaultMaxMultipartMemory = 32 << 20
+
+// sniffLen is the number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// MultipartForm parses the multipart request body and returns the form,
+// storing up to maxMemory bytes of file parts in memory and the remainder in
+// temporary files on disk. The form is only parsed once, so subsequent calls
+// return the same form.
+//
+// Temporary files are removed by the router after the response is written.
+func (r Request[Data]) MultipartForm(maxMemory int64) (*multipart.Form, error) {
+	req := r.Request()
+
+	if req.MultipartForm == nil {
+		if err := req.ParseMultipartForm(maxMemory); err != nil {
+			return nil, err
+		}
+	}
+
+	return req.MultipartForm, nil
+}
+
+// File returns the first file uploaded in the named multipart form field,
+// parsing the form with DefaultMaxMultipartMemory if it has not been parsed.
+// http.ErrMissingFile is returned if no file was uploaded in the field.
+func (r Request[Data]) File(name string) (*multipart.FileHeader, error) {
+	files, err := r.Files(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return files[0], nil
+}
+
+// Files returns the files uploaded in the named multipart form field, parsing
+// the form with DefaultMaxMultipartMemory if it has not been parsed.
+// http.ErrMissingFile is returned if no files were uploaded in the field.
+func (r Request[Data]) Files(name string) ([]*multipart.FileHeader, error) {
+	form, err := r.MultipartForm(DefaultMaxMultipartMemory)
+	if err != nil {
+		return nil, err
+	}
+
+	files := form.File[name]
+	if len(files) == 0 {
+		return nil, http.ErrMissingFile
+	}
+
+	return files, nil
+}
+
+// EachPart reads the multipart request body one part at a time, calling fn
+// for each part. Unlike MultipartForm, parts are not buffered in memory or
+// written to disk, which makes EachPart suitable for large uploads that are
+// streamed elsewhere.
+//
+// Iteration stops when fn returns an error, which is returned by EachPart.
+// Each part is closed after fn returns, so parts must not be retained.
+func (r Request[Data]) EachPart(fn func(part *Part) error) error {
+	reader, err := r.Request().MultipartReader()
+	if err != nil {
+		return err
+	}
+
+	for {
+		rawPart, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		part := &Part{Part: rawPart, reader: bufio.NewReaderSize(rawPart, sniffLen)}
+		err = fn(part)
+		rawPart.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Part is a single part of a multipart request body passed to the function
+// given to Request.EachPart.
+type Part struct {
+	*multipart.Part
+	reader *bufio.Reader
+}
+
+// Read reads the body of the part.
+func (p *Part) Read(b []byte) (int, error) {
+	return p.reader.Read(b)
+}
+
+// IsFile returns true if the part is a file upload.
+func (p *Part) IsFile() bool {
+	return p.FileName() != ""
+}
+
+// ContentType returns the content type of the part detected from the start of
+// its body using http.DetectContentType. The Content-Type header sent by the
+// client is available via the part's Header, but shouldn't be trusted.
+func (p *Part) ContentType() string {
+	// Peek returns an error when the body is shorter than sniffLen, which is
+	// expected for small parts.
+	data, _ := p.reader.Peek(sniffLen)
+
+	return http.DetectContentType(data)
+}
+
+// DetectContentType returns the content type of the uploaded file detected
+// from the start of its content using http.DetectContentType. The
+// Content-Type header sent by the client is available via the file's Header,
+// but shouldn't be trusted.
+func DetectContentType(file *multipart.FileHeader) (string, error) {
+	f, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	data := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, data)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return http.DetectContentType(data[:n]), nil
+}
+
+// removeMultipartFiles removes the temporary files created when parsing the
+// request's multipart form, if any.
+func removeMultipartFiles(r *http.Request) {
+	if r.MultipartForm != nil {
+		_ = r.MultipartForm.RemoveAll()
+	}
+}
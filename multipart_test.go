@@ -0,0 +1,193 @@
+package medium
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var pngHeader = []byte("\x89PNG\x0D\x0A\x1A\x0A")
+
+func newMultipartRequest(t *testing.T, path string, fields map[string]string, files map[string][][]byte) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+
+	for name, contents := range files {
+		for i, content := range contents {
+			part, err := writer.CreateFormFile(name, fmt.Sprintf("%s-%d", name, i))
+			require.NoError(t, err)
+
+			_, err = part.Write(content)
+			require.NoError(t, err)
+		}
+	}
+
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return req
+}
+
+func TestRequest_File(t *testing.T) {
+	router := New(WithNoData)
+	router.Post("/upload", func(ctx context.Context, r *Request[NoData]) Response {
+		file, err := r.File("avatar")
+		require.NoError(t, err)
+		require.Equal(t, "avatar-0", file.Filename)
+
+		contentType, err := DetectContentType(file)
+		require.NoError(t, err)
+
+		attachments, err := r.Files("attachments")
+		require.NoError(t, err)
+
+		_, err = r.File("missing")
+		require.ErrorIs(t, err, http.ErrMissingFile)
+
+		return StringResponse(http.StatusOK, fmt.Sprintf("%s %s %d", r.FormValue("name"), contentType, len(attachments)))
+	})
+
+	req := newMultipartRequest(t, "/upload",
+		map[string]string{"name": "Fox Mulder"},
+		map[string][][]byte{
+			"avatar":      {append(pngHeader, "image"...)},
+			"attachments": {[]byte("one"), []byte("two")},
+		},
+	)
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "Fox Mulder image/png 2", rw.Body.String())
+}
+
+func TestRequest_MultipartForm_RemovesTempFiles(t *testing.T) {
+	var tempFile string
+
+	router := New(WithNoData)
+	router.Post("/upload", func(ctx context.Context, r *Request[NoData]) Response {
+		// A maxMemory of zero writes every file to disk.
+		form, err := r.MultipartForm(0)
+		require.NoError(t, err)
+
+		file, err := form.File["avatar"][0].Open()
+		require.NoError(t, err)
+		defer file.Close()
+
+		osFile, ok := file.(*os.File)
+		require.True(t, ok)
+		tempFile = osFile.Name()
+
+		return OK()
+	})
+
+	req := newMultipartRequest(t, "/upload", nil, map[string][][]byte{"avatar": {pngHeader}})
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.NotEmpty(t, tempFile)
+
+	_, err := os.Stat(tempFile)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestRequest_MultipartForm_NotMultipart(t *testing.T) {
+	router := New(WithNoData)
+	router.Post("/upload", func(ctx context.Context, r *Request[NoData]) Response {
+		_, err := r.File("avatar")
+		require.ErrorIs(t, err, http.ErrNotMultipart)
+
+		return OK()
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("name=Fox"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+}
+
+func TestRequest_EachPart(t *testing.T) {
+	router := New(WithNoData)
+	router.Post("/upload", func(ctx context.Context, r *Request[NoData]) Response {
+		var parts []string
+
+		err := r.EachPart(func(part *Part) error {
+			if !part.IsFile() {
+				value, err := io.ReadAll(part)
+				require.NoError(t, err)
+				parts = append(parts, part.FormName()+"="+string(value))
+
+				return nil
+			}
+
+			contentType := part.ContentType()
+			content, err := io.ReadAll(part)
+			require.NoError(t, err)
+
+			parts = append(parts, fmt.Sprintf("%s:%s:%d", part.FileName(), contentType, len(content)))
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		return StringResponse(http.StatusOK, strings.Join(parts, ","))
+	})
+
+	req := newMultipartRequest(t, "/upload",
+		map[string]string{"name": "Fox Mulder"},
+		map[string][][]byte{"avatar": {append(pngHeader, bytes.Repeat([]byte("a"), 1024)...)}},
+	)
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "name=Fox Mulder,avatar-0:image/png:1032", rw.Body.String())
+}
+
+func TestRequest_EachPart_StopsOnError(t *testing.T) {
+	errStop := errors.New("stop")
+
+	router := New(WithNoData)
+	router.Post("/upload", func(ctx context.Context, r *Request[NoData]) Response {
+		calls := 0
+
+		err := r.EachPart(func(part *Part) error {
+			calls++
+			return errStop
+		})
+		require.ErrorIs(t, err, errStop)
+		require.Equal(t, 1, calls)
+
+		return OK()
+	})
+
+	req := newMultipartRequest(t, "/upload", nil, map[string][][]byte{"attachments": {[]byte("one"), []byte("two")}})
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+}
@@ -0,0 +1,164 @@
+package medium
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Format is a content type that can be rendered by Respond.
+type Format struct {
+	// ContentType is the media type rendered, e.g. `application/json`.
+	ContentType string
+	// Render returns the response for the format. It's only called if the
+	// format is selected.
+	Render func() Response
+}
+
+// Respond renders the format that best matches the request's Accept header,
+// preferring formats earlier in the list when the client has no preference.
+// If no format is acceptable, a 406 Not Acceptable response is returned.
+//
+// The Vary header is set to Accept so that caches store each format
+// separately, and the Content-Type header is set to the selected format if the
+// rendered response didn't set one.
+func Respond(r requestable, formats ...Format) Response {
+	offers := make([]string, len(formats))
+	for i, format := range formats {
+		offers[i] = format.ContentType
+	}
+
+	best := negotiate(r.Request().Header.Values("Accept"), offers)
+	if best == -1 {
+		res := StringResponse(http.StatusNotAcceptable, "406 not acceptable")
+		res.Header().Add("Vary", "Accept")
+
+		return res
+	}
+
+	res := formats[best].Render()
+	res.Header().Add("Vary", "Accept")
+	if res.Header().Get("Content-Type") == "" {
+		res.Header().Set("Content-Type", formats[best].ContentType)
+	}
+
+	return res
+}
+
+// Accepts returns the offered media type that best matches the request's
+// Accept header, taking q-values and specificity into account. Offers earlier
+// in the list are preferred when the client has no preference. An empty string
+// is returned if none of the offers are acceptable.
+func (r Request[Data]) Accepts(offers ...string) string {
+	best := negotiate(r.Request().Header.Values("Accept"), offers)
+	if best == -1 {
+		return ""
+	}
+
+	return offers[best]
+}
+
+// acceptRange is a single value of an Accept style header along with its
+// quality.
+type acceptRange struct {
+	value string
+	q     float64
+}
+
+// parseAccept parses the values of an Accept style header, e.g. Accept or
+// Accept-Encoding, ignoring parameters other than q. Values with an invalid
+// q-value are ignored.
+func parseAccept(values []string) []acceptRange {
+	ranges := make([]acceptRange, 0, len(values))
+
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			name, params, _ := strings.Cut(part, ";")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+
+			accept := acceptRange{value: name, q: 1}
+			valid := true
+
+			for _, param := range strings.Split(params, ";") {
+				key, value, _ := strings.Cut(param, "=")
+				if !strings.EqualFold(strings.TrimSpace(key), "q") {
+					continue
+				}
+
+				q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				if err != nil || q < 0 || q > 1 {
+					valid = false
+					break
+				}
+
+				accept.q = q
+			}
+
+			if valid {
+				ranges = append(ranges, accept)
+			}
+		}
+	}
+
+	return ranges
+}
+
+// negotiate returns the index of the offered media type that best matches the
+// Accept header values, or -1 if none are acceptable. If no Accept header was
+// sent the first offer is returned.
+func negotiate(accept []string, offers []string) int {
+	if len(offers) == 0 {
+		return -1
+	}
+
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return 0
+	}
+
+	best := -1
+	bestQ := 0.0
+
+	for i, offer := range offers {
+		if q := mediaTypeQuality(ranges, offer); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+
+	return best
+}
+
+// mediaTypeQuality returns the quality of the most specific range matching
+// offer, or 0 if no range matches.
+func mediaTypeQuality(ranges []acceptRange, offer string) float64 {
+	mediaType, _, _ := strings.Cut(offer, ";")
+	offerType, offerSubtype, _ := strings.Cut(strings.ToLower(strings.TrimSpace(mediaType)), "/")
+
+	q := 0.0
+	specificity := -1
+
+	for _, accept := range ranges {
+		acceptType, acceptSubtype, _ := strings.Cut(accept.value, "/")
+
+		var matched int
+		switch {
+		case acceptType == offerType && acceptSubtype == offerSubtype:
+			matched = 2
+		case acceptType == offerType && acceptSubtype == "*":
+			matched = 1
+		case acceptType == "*" && acceptSubtype == "*":
+			matched = 0
+		default:
+			continue
+		}
+
+		if matched > specificity {
+			q, specificity = accept.q, matched
+		}
+	}
+
+	return q
+}
@@ -0,0 +1,85 @@
+package medium
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest_Accepts(t *testing.T) {
+	testCases := map[string]struct {
+		accept   string
+		offers   []string
+		expected string
+	}{
+		"no header":           {accept: "", offers: []string{"text/html", "application/json"}, expected: "text/html"},
+		"exact":               {accept: "application/json", offers: []string{"text/html", "application/json"}, expected: "application/json"},
+		"q-values":            {accept: "text/html;q=0.5, application/json", offers: []string{"text/html", "application/json"}, expected: "application/json"},
+		"wildcard":            {accept: "*/*", offers: []string{"application/json", "text/html"}, expected: "application/json"},
+		"subtype wildcard":    {accept: "text/*, application/json;q=0.1", offers: []string{"application/json", "text/plain"}, expected: "text/plain"},
+		"specific overrides":  {accept: "text/*;q=0.9, text/plain;q=0", offers: []string{"text/plain", "text/html"}, expected: "text/html"},
+		"browser":             {accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", offers: []string{"application/json", "text/html"}, expected: "text/html"},
+		"tie prefers offers":  {accept: "application/json, text/html", offers: []string{"text/html", "application/json"}, expected: "text/html"},
+		"case insensitive":    {accept: "Application/JSON", offers: []string{"application/json"}, expected: "application/json"},
+		"offer params":        {accept: "text/plain", offers: []string{"text/plain; charset=utf-8"}, expected: "text/plain; charset=utf-8"},
+		"not acceptable":      {accept: "application/xml", offers: []string{"text/html", "application/json"}, expected: ""},
+		"explicitly rejected": {accept: "application/json;q=0", offers: []string{"application/json"}, expected: ""},
+		"invalid q ignored":   {accept: "application/json;q=2, text/html", offers: []string{"application/json", "text/html"}, expected: "text/html"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+
+			require.Equal(t, tc.expected, NewRequest(req, NoData{}, nil).Accepts(tc.offers...))
+		})
+	}
+}
+
+func TestRespond(t *testing.T) {
+	router := New(WithNoData)
+	router.Get("/user", func(ctx context.Context, r *Request[NoData]) Response {
+		return Respond(r,
+			Format{ContentType: "text/html", Render: func() Response {
+				return StringResponse(http.StatusOK, "<h1>Dana</h1>")
+			}},
+			Format{ContentType: "application/json", Render: func() Response {
+				return JSON(http.StatusOK, map[string]string{"name": "Dana"})
+			}},
+		)
+	})
+
+	testCases := map[string]struct {
+		accept      string
+		status      int
+		contentType string
+		body        string
+	}{
+		"html": {accept: "text/html", status: http.StatusOK, contentType: "text/html", body: "<h1>Dana</h1>"},
+		"json": {accept: "application/json", status: http.StatusOK, contentType: "application/json; charset=utf-8", body: "{\"name\":\"Dana\"}\n"},
+		"none": {accept: "application/xml", status: http.StatusNotAcceptable, body: "406 not acceptable"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/user", nil)
+			req.Header.Set("Accept", tc.accept)
+			rw := httptest.NewRecorder()
+
+			router.ServeHTTP(rw, req)
+
+			require.Equal(t, tc.status, rw.Code)
+			require.Equal(t, "Accept", rw.Header().Get("Vary"))
+			if tc.contentType != "" {
+				require.Equal(t, tc.contentType, rw.Header().Get("Content-Type"))
+			}
+			require.Equal(t, tc.body, rw.Body.String())
+		})
+	}
+}
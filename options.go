@@ -0,0 +1,130 @@
+package medium
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// RouteOption configures a route when it is registered via Match, Get, Post,
+// etc.
+type RouteOption func(*routeOptions)
+
+// routeOptions holds the configuration provided by RouteOptions.
+type routeOptions struct {
+	name        string
+	limits      limits
+	befores     []routeBefore
+	middlewares []Middleware
+	metadata    map[string]any
+}
+
+// WithName names the route so that its URL can be generated via
+// Router.URLFor. Names must be unique across the router and its groups.
+func WithName(name string) RouteOption {
+	return func(o *routeOptions) {
+		o.name = name
+	}
+}
+
+// WithMaxBodySize limits the size of the route's request body to n bytes,
+// overriding the limit set on its router or group. A negative value removes
+// the limit. See RouteGroup.MaxBodySize for more information.
+func WithMaxBodySize(n int64) RouteOption {
+	return func(o *routeOptions) {
+		o.limits.maxBodySize = n
+	}
+}
+
+// WithReadTimeout limits how long the client has to send the route's request
+// body, overriding the timeout set on its router or group. A negative value
+// removes the timeout. See RouteGroup.ReadTimeout for more information.
+func WithReadTimeout(timeout time.Duration) RouteOption {
+	return func(o *routeOptions) {
+		o.limits.readTimeout = timeout
+	}
+}
+
+// WithTimeout limits how long the route's handler has to return a response,
+// overriding the timeout set on its router or group. A negative value removes
+// the timeout. See RouteGroup.Timeout for more information.
+func WithTimeout(timeout time.Duration) RouteOption {
+	return func(o *routeOptions) {
+		o.limits.timeout = timeout
+	}
+}
+
+// WithBefore adds BeforeFuncs that are only called for the route, after the
+// BeforeFuncs of the groups it's defined in.
+//
+// T must match the data type of the router or group the route is registered
+// on. Since RouteOptions aren't tied to a data type, this can't be checked at
+// compile time, so a mismatch panics when the route is registered.
+func WithBefore[T any](befores ...BeforeFunc[T]) RouteOption {
+	return func(o *routeOptions) {
+		data := reflect.TypeOf((*T)(nil)).Elem()
+		for _, before := range befores {
+			o.befores = append(o.befores, routeBefore{fn: before, data: data})
+		}
+	}
+}
+
+// WithMiddleware adds middleware that is only called for the route, after the
+// middleware of the groups it's defined in. See RouteGroup.Use for more
+// information.
+func WithMiddleware(middlewares ...Middleware) RouteOption {
+	return func(o *routeOptions) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+// WithMetadata attaches a value to the route that can be read by BeforeFuncs
+// and handlers via Request.Metadata, e.g. to declare the permission a route
+// requires.
+func WithMetadata(key string, value any) RouteOption {
+	return func(o *routeOptions) {
+		if o.metadata == nil {
+			o.metadata = make(map[string]any)
+		}
+
+		o.metadata[key] = value
+	}
+}
+
+func newRouteOptions(opts []RouteOption) *routeOptions {
+	options := &routeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// routeBefore is a BeforeFunc added via WithBefore along with its data type,
+// which is checked against the route's data type when it's registered.
+type routeBefore struct {
+	fn   any
+	data reflect.Type
+}
+
+// routeBefores returns the BeforeFuncs added via WithBefore as BeforeFuncs of
+// the route's data type. It panics if a BeforeFunc has a different data type.
+func routeBefores[T any](path string, befores []routeBefore) []BeforeFunc[T] {
+	typed := make([]BeforeFunc[T], 0, len(befores))
+
+	for _, before := range befores {
+		typedBefore, ok := before.fn.(BeforeFunc[T])
+		if !ok {
+			panic(fmt.Sprintf(
+				"WithBefore for route %s expects BeforeFuncs with data type %s, got %s",
+				path,
+				reflect.TypeOf((*T)(nil)).Elem(),
+				before.data,
+			))
+		}
+
+		typed = append(typed, typedBefore)
+	}
+
+	return typed
+}
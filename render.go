@@ -0,0 +1,290 @@
+package medium
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"sync"
+
+	"github.com/blakewilliams/bat"
+	"github.com/blakewilliams/medium/mlog"
+)
+
+// RendererConfig configures how templates are loaded and rendered by a
+// Renderer.
+type RendererConfig struct {
+	// Extension is the file extension of templates. Templates are named by
+	// their path relative to the root of the file system, e.g.
+	// `users/show.html`. Defaults to `.html`.
+	Extension string
+	// Layout is the name of the template that wraps rendered templates. The
+	// rendered template is available to the layout as ChildContent. No layout
+	// is used if empty.
+	Layout string
+	// Escape escapes values output by templates. Defaults to bat.HTMLEscape.
+	Escape func(string) string
+	// Reload re-reads templates from the file system each time a template is
+	// rendered, so that changes are visible without restarting the server.
+	// This is intended for development and should be used with os.DirFS.
+	Reload bool
+}
+
+// Renderer renders bat templates loaded from a file system. Templates can
+// render other templates using the `partial` helper, e.g.
+// `{{partial("users/_card.html", {user: user})}}`.
+//
+// Renderers can be attached to a Router via Router.Renderer, which allows
+// default data for every template to be built from the request's Data.
+type Renderer[T any] struct {
+	fsys        fs.FS
+	config      RendererConfig
+	mu          sync.RWMutex
+	engine      *bat.Engine
+	helpers     map[string]any
+	defaultData func(ctx context.Context, r *Request[T]) map[string]any
+}
+
+// NewRenderer returns a Renderer that renders the templates in fsys. An error
+// is returned if the templates can't be loaded or parsed.
+func NewRenderer[T any](fsys fs.FS, config RendererConfig) (*Renderer[T], error) {
+	if config.Extension == "" {
+		config.Extension = ".html"
+	}
+
+	if config.Escape == nil {
+		config.Escape = bat.HTMLEscape
+	}
+
+	renderer := &Renderer[T]{fsys: fsys, config: config, helpers: make(map[string]any)}
+
+	engine, err := renderer.load()
+	if err != nil {
+		return nil, err
+	}
+	renderer.engine = engine
+
+	return renderer, nil
+}
+
+// Helper adds a helper function that can be called from templates.
+func (rr *Renderer[T]) Helper(name string, fn any) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	rr.helpers[name] = fn
+	rr.engine.Helper(name, fn)
+}
+
+// Data sets the function used to build the default data passed to every
+// template rendered for a request, e.g. the current user. Data passed to
+// Render takes precedence over default data with the same key.
+//
+// Default data is only available once the Renderer has been attached to a
+// Router via Router.Renderer.
+func (rr *Renderer[T]) Data(fn func(ctx context.Context, r *Request[T]) map[string]any) {
+	rr.defaultData = fn
+}
+
+// Render returns a response with the given status containing the named
+// template rendered with data. The template is wrapped in the configured
+// layout, if any.
+func (rr *Renderer[T]) Render(status int, template string, data map[string]any) *TemplateResponse {
+	header := http.Header{}
+	header.Set("Content-Type", "text/html; charset=utf-8")
+
+	return &TemplateResponse{
+		status:   status,
+		header:   header,
+		template: template,
+		layout:   rr.config.Layout,
+		data:     data,
+		render:   rr.render,
+	}
+}
+
+// load creates a new engine with the templates and helpers registered.
+func (rr *Renderer[T]) load() (*bat.Engine, error) {
+	engine := bat.NewEngine(rr.config.Escape)
+	for name, fn := range rr.helpers {
+		engine.Helper(name, fn)
+	}
+
+	if err := engine.AutoRegister(rr.fsys, "", rr.config.Extension); err != nil {
+		return nil, err
+	}
+
+	return engine, nil
+}
+
+// render renders the template, wrapping it in layout if provided.
+func (rr *Renderer[T]) render(w io.Writer, template string, layout string, data map[string]any) error {
+	rr.mu.RLock()
+	engine := rr.engine
+	rr.mu.RUnlock()
+
+	if rr.config.Reload {
+		rr.mu.RLock()
+		reloaded, err := rr.load()
+		rr.mu.RUnlock()
+
+		if err != nil {
+			return err
+		}
+
+		engine = reloaded
+	}
+
+	if layout == "" {
+		return engine.Render(w, template, data)
+	}
+
+	childContent := new(bytes.Buffer)
+	if err := engine.Render(childContent, template, data); err != nil {
+		return err
+	}
+
+	layoutData := make(map[string]any, len(data)+1)
+	for key, value := range data {
+		layoutData[key] = value
+	}
+	layoutData["ChildContent"] = bat.Safe(childContent.String())
+
+	return engine.Render(w, layout, layoutData)
+}
+
+// resolve adds the renderer's default data to template responses and renders
+// them, logging any errors.
+func (rr *Renderer[T]) resolve(ctx context.Context, r *Request[T], res Response) Response {
+	if rr == nil {
+		return res
+	}
+
+	tr, ok := res.(*TemplateResponse)
+	if !ok || tr.rendered {
+		return res
+	}
+
+	if rr.defaultData != nil {
+		data := make(map[string]any)
+		for key, value := range rr.defaultData(ctx, r) {
+			data[key] = value
+		}
+		for key, value := range tr.data {
+			data[key] = value
+		}
+		tr.data = data
+	}
+
+	if err := tr.execute(); err != nil {
+		mlog.Error(ctx, "error rendering template", mlog.Fields{"template": tr.template, "error": err})
+	}
+
+	return tr
+}
+
+// resolverKey is the context key used to pass the router's Renderer resolve
+// function to groups.
+type resolverKey struct{}
+
+// withResolver returns a copy of ctx carrying a function that resolves
+// template responses for r, so that groups nested within the router can add
+// its default data before running their own AfterFuncs.
+func (rr *Renderer[T]) withResolver(ctx context.Context, r *Request[T]) context.Context {
+	if rr == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, resolverKey{}, func(res Response) Response {
+		return rr.resolve(ctx, r, res)
+	})
+}
+
+// resolveTemplate resolves res using the resolve function added to ctx by
+// withResolver, if any.
+func resolveTemplate(ctx context.Context, res Response) Response {
+	resolve, ok := ctx.Value(resolverKey{}).(func(Response) Response)
+	if !ok {
+		return res
+	}
+
+	return resolve(res)
+}
+
+// TemplateResponse is a Response that renders a template. See Renderer.Render
+// for more information.
+type TemplateResponse struct {
+	status   int
+	header   http.Header
+	template string
+	layout   string
+	data     map[string]any
+	render   func(w io.Writer, template string, layout string, data map[string]any) error
+
+	rendered bool
+	body     *bytes.Buffer
+	err      error
+}
+
+var _ Response = (*TemplateResponse)(nil)
+
+// Layout sets the layout used to wrap the template, overriding the
+// Renderer's default layout. An empty name renders the template without a
+// layout.
+func (tr *TemplateResponse) Layout(name string) *TemplateResponse {
+	tr.layout = name
+
+	return tr
+}
+
+// Status returns the status code of the response, or 500 if the template
+// could not be rendered.
+func (tr *TemplateResponse) Status() int {
+	if tr.execute() != nil {
+		return http.StatusInternalServerError
+	}
+
+	return tr.status
+}
+
+// Header returns the header map for the response.
+func (tr *TemplateResponse) Header() http.Header { return tr.header }
+
+// Body returns the rendered template.
+func (tr *TemplateResponse) Body() io.Reader {
+	if tr.execute() != nil {
+		return bytes.NewReader([]byte("500 internal server error"))
+	}
+
+	return bytes.NewReader(tr.body.Bytes())
+}
+
+// Err returns the error encountered rendering the template, if any.
+func (tr *TemplateResponse) Err() error {
+	return tr.execute()
+}
+
+// execute renders the template if it hasn't been rendered yet.
+func (tr *TemplateResponse) execute() (err error) {
+	if tr.rendered {
+		return tr.err
+	}
+
+	tr.rendered = true
+	tr.body = new(bytes.Buffer)
+
+	defer func() {
+		// Helpers like partial panic on errors, so recover and report them
+		// as render errors.
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic rendering template %s: %v", tr.template, recovered)
+			tr.err = err
+		}
+	}()
+
+	tr.err = tr.render(tr.body, tr.template, tr.layout, tr.data)
+
+	return tr.err
+}
@@ -0,0 +1,166 @@
+package medium
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed all:testdata/views
+var viewsFS embed.FS
+
+type renderData struct {
+	currentUser string
+}
+
+func newTestRenderer(t *testing.T, config RendererConfig) *Renderer[renderData] {
+	t.Helper()
+
+	views, err := fs.Sub(viewsFS, "testdata/views")
+	require.NoError(t, err)
+
+	renderer, err := NewRenderer[renderData](views, config)
+	require.NoError(t, err)
+
+	return renderer
+}
+
+func TestRenderer(t *testing.T) {
+	renderer := newTestRenderer(t, RendererConfig{Layout: "layouts/application.html"})
+	renderer.Data(func(ctx context.Context, r *Request[renderData]) map[string]any {
+		return map[string]any{"currentUser": r.Data.currentUser, "title": "Default"}
+	})
+
+	router := New(func(r *RootRequest) renderData { return renderData{currentUser: "Fox"} })
+	router.Renderer(renderer)
+	router.Get("/users/:name", func(ctx context.Context, r *Request[renderData]) Response {
+		return renderer.Render(http.StatusOK, "users/show.html", map[string]any{
+			"title": "<Profile>",
+			"user":  r.Param("name"),
+			"role":  "agent",
+		})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/dana", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "text/html; charset=utf-8", rw.Header().Get("Content-Type"))
+	require.Equal(
+		t,
+		"<title>&lt;Profile&gt;</title>\n<main><h1>dana</h1><span>agent</span>\n signed in as Fox\n</main>\n",
+		rw.Body.String(),
+	)
+}
+
+func TestRenderer_SubgroupAndMissing(t *testing.T) {
+	renderer := newTestRenderer(t, RendererConfig{})
+	renderer.Data(func(ctx context.Context, r *Request[renderData]) map[string]any {
+		return map[string]any{"currentUser": r.Data.currentUser}
+	})
+
+	router := New(func(r *RootRequest) renderData { return renderData{currentUser: "Fox"} })
+	router.Renderer(renderer)
+	router.Missing(func(ctx context.Context, r *Request[renderData]) Response {
+		return renderer.Render(http.StatusNotFound, "users/show.html", map[string]any{"user": "nobody"})
+	})
+
+	group := Group(router, func(r *Request[renderData]) NoData { return NoData{} })
+	group.Get("/admin", func(ctx context.Context, r *Request[NoData]) Response {
+		return renderer.Render(http.StatusOK, "users/show.html", map[string]any{"user": "admin"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, "<h1>admin</h1><span></span>\n signed in as Fox\n", rw.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/missing", nil)
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusNotFound, rw.Code)
+	require.Equal(t, "<h1>nobody</h1><span></span>\n signed in as Fox\n", rw.Body.String())
+}
+
+func TestRenderer_SubgroupAfter(t *testing.T) {
+	renderer := newTestRenderer(t, RendererConfig{})
+	renderer.Data(func(ctx context.Context, r *Request[renderData]) map[string]any {
+		return map[string]any{"currentUser": r.Data.currentUser}
+	})
+
+	router := New(func(r *RootRequest) renderData { return renderData{currentUser: "Fox"} })
+	router.Renderer(renderer)
+
+	group := Group(router, func(r *Request[renderData]) NoData { return NoData{} })
+	group.After(func(ctx context.Context, r *Request[NoData], res Response) Response {
+		res.Header().Set("X-Status", fmt.Sprint(res.Status()))
+		return res
+	})
+	group.Get("/admin", func(ctx context.Context, r *Request[NoData]) Response {
+		return renderer.Render(http.StatusOK, "users/show.html", map[string]any{"user": "admin"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, "200", rw.Header().Get("X-Status"))
+	require.Equal(t, "<h1>admin</h1><span></span>\n signed in as Fox\n", rw.Body.String())
+}
+
+func TestRenderer_LayoutOverrideAndErrors(t *testing.T) {
+	renderer := newTestRenderer(t, RendererConfig{Layout: "layouts/application.html"})
+
+	res := renderer.Render(http.StatusOK, "users/_badge.html", map[string]any{"role": "admin"}).Layout("")
+	body, err := io.ReadAll(res.Body())
+	require.NoError(t, err)
+	require.Equal(t, "<span>admin</span>\n", string(body))
+
+	res = renderer.Render(http.StatusOK, "users/missing.html", nil)
+	require.Error(t, res.Err())
+	require.Equal(t, http.StatusInternalServerError, res.Status())
+}
+
+func TestRenderer_Reload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hello.html")
+	require.NoError(t, os.WriteFile(path, []byte("hello {{name}}"), 0o644))
+
+	renderer, err := NewRenderer[NoData](os.DirFS(dir), RendererConfig{Reload: true})
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(renderer.Render(http.StatusOK, "hello.html", map[string]any{"name": "Dana"}).Body())
+	require.NoError(t, err)
+	require.Equal(t, "hello Dana", string(body))
+
+	require.NoError(t, os.WriteFile(path, []byte("goodbye {{name}}"), 0o644))
+
+	body, err = io.ReadAll(renderer.Render(http.StatusOK, "hello.html", map[string]any{"name": "Dana"}).Body())
+	require.NoError(t, err)
+	require.Equal(t, "goodbye Dana", string(body))
+}
+
+func TestRenderer_Helper(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shout.html"), []byte("{{shout(name)}}"), 0o644))
+
+	renderer, err := NewRenderer[NoData](os.DirFS(dir), RendererConfig{Reload: true})
+	require.NoError(t, err)
+	renderer.Helper("shout", func(s string) string { return s + "!" })
+
+	body, err := io.ReadAll(renderer.Render(http.StatusOK, "shout.html", map[string]any{"name": "hey"}).Body())
+	require.NoError(t, err)
+	require.Equal(t, "hey!", string(body))
+}
@@ -1,11 +1,19 @@
 package medium
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 )
 
+// ErrMissingParam is returned by the typed param accessors when the requested
+// param was not matched by the route.
+var ErrMissingParam = errors.New("route param not present")
+
 // RootRequest is a wrapper around http.Request that contains the original Request
 // object and the response writer. This is used for the root router since there is
 // no application specific data to store.
@@ -26,6 +34,49 @@ type RouteData struct {
 	Params map[string]string
 	// HandlerPath holds the path that was matched.
 	HandlerPath string
+	// Metadata holds the values attached to the matched route via
+	// WithMetadata.
+	Metadata map[string]any
+}
+
+// routeDataKey is the context key used to store a *routeDataHolder.
+type routeDataKey struct{}
+
+// routeDataHolder records the route matched by the router so that middleware,
+// which is called before routing, can access it via RouteDataFrom.
+type routeDataHolder struct {
+	routeData *RouteData
+}
+
+// RouteDataFrom returns the data of the route matched by the router handling
+// the request ctx belongs to. This allows middleware to access the matched
+// route after calling next, e.g. to display it on error pages. false is
+// returned if no route has been matched.
+func RouteDataFrom(ctx context.Context) (*RouteData, bool) {
+	holder, ok := ctx.Value(routeDataKey{}).(*routeDataHolder)
+	if !ok || holder.routeData == nil {
+		return nil, false
+	}
+
+	return holder.routeData, true
+}
+
+// withRouteDataHolder returns r with a routeDataHolder added to its context,
+// unless one is already present, e.g. when routers are mounted.
+func withRouteDataHolder(r *http.Request) *http.Request {
+	if _, ok := r.Context().Value(routeDataKey{}).(*routeDataHolder); ok {
+		return r
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), routeDataKey{}, &routeDataHolder{}))
+}
+
+// recordRouteData records routeData as the matched route of the request ctx
+// belongs to.
+func recordRouteData(ctx context.Context, routeData *RouteData) {
+	if holder, ok := ctx.Value(routeDataKey{}).(*routeDataHolder); ok {
+		holder.routeData = routeData
+	}
 }
 
 func NewRequest[Data any](originalRequest *http.Request, data Data, routeData *RouteData) *Request[Data] {
@@ -35,12 +86,100 @@ func NewRequest[Data any](originalRequest *http.Request, data Data, routeData *R
 // Request returns the original request.
 func (r Request[Data]) Request() *http.Request { return r.originalRequest }
 
-// Params returns the route parameters that were matched.
-func (r Request[Data]) Params() map[string]string { return r.routeData.Params }
+// Params returns the route parameters that were matched, or nil if no route
+// was matched.
+func (r Request[Data]) Params() map[string]string {
+	if r.routeData == nil {
+		return nil
+	}
+
+	return r.routeData.Params
+}
+
+// Param returns the named route parameter, or an empty string if the
+// parameter was not matched.
+func (r Request[Data]) Param(name string) string {
+	if r.routeData == nil {
+		return ""
+	}
+
+	return r.routeData.Params[name]
+}
+
+// ParamInt returns the named route parameter parsed as an int. An error is
+// returned if the parameter was not matched or is not a valid int.
+func (r Request[Data]) ParamInt(name string) (int, error) {
+	value, err := r.param(name)
+	if err != nil {
+		return 0, err
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("param %s is not an int: %w", name, err)
+	}
+
+	return parsed, nil
+}
+
+// ParamInt64 returns the named route parameter parsed as an int64. An error is
+// returned if the parameter was not matched or is not a valid int64.
+func (r Request[Data]) ParamInt64(name string) (int64, error) {
+	value, err := r.param(name)
+	if err != nil {
+		return 0, err
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("param %s is not an int64: %w", name, err)
+	}
+
+	return parsed, nil
+}
+
+// ParamUUID returns the named route parameter parsed as a UUID. An error is
+// returned if the parameter was not matched or is not a valid UUID.
+func (r Request[Data]) ParamUUID(name string) (UUID, error) {
+	value, err := r.param(name)
+	if err != nil {
+		return UUID{}, err
+	}
+
+	parsed, err := ParseUUID(value)
+	if err != nil {
+		return UUID{}, fmt.Errorf("param %s is not a uuid: %w", name, err)
+	}
+
+	return parsed, nil
+}
+
+func (r Request[Data]) param(name string) (string, error) {
+	if r.routeData == nil {
+		return "", fmt.Errorf("%w: %s", ErrMissingParam, name)
+	}
+
+	value, ok := r.routeData.Params[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrMissingParam, name)
+	}
+
+	return value, nil
+}
 
 // MatchedPath returns the route path pattern that was matched.
 func (r Request[Data]) MatchedPath() string { return r.routeData.HandlerPath }
 
+// Metadata returns the value attached to the matched route via WithMetadata,
+// or nil if the route has no value for key.
+func (r Request[Data]) Metadata(key string) any {
+	if r.routeData == nil {
+		return nil
+	}
+
+	return r.routeData.Metadata[key]
+}
+
 // URL returns the url.URL of the *http.Request.
 func (r Request[Data]) URL() *url.URL { return r.Request().URL }
 
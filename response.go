@@ -61,7 +61,7 @@ func Redirect(to string) Response {
 type ResponseBuilder struct {
 	status int
 	header http.Header
-	body   io.Reader
+	body   *bytes.Buffer
 }
 
 var _ io.Writer = (*ResponseBuilder)(nil)
@@ -72,15 +72,14 @@ var _ Response = (*ResponseBuilder)(nil)
 // headers.
 func (rb *ResponseBuilder) WriteStatus(status int) { rb.status = status }
 
-// Write writes the provided bytes to the response body.
+// Write writes the provided bytes to the response body. The body is buffered
+// until the response is sent, use Stream to write large or slow responses.
 func (rb *ResponseBuilder) Write(p []byte) (int, error) {
 	if rb.body == nil {
-		rb.body = bytes.NewReader(p)
-	} else {
-		rb.body = io.MultiReader(rb.body, bytes.NewReader(p))
+		rb.body = new(bytes.Buffer)
 	}
 
-	return len(p), nil
+	return rb.body.Write(p)
 }
 
 // WriteString writes the provided string to the response body.
@@ -89,7 +88,13 @@ func (rb *ResponseBuilder) WriteString(s string) (int, error) {
 }
 
 // Body returns the body of the response.
-func (rb *ResponseBuilder) Body() io.Reader { return rb.body }
+func (rb *ResponseBuilder) Body() io.Reader {
+	if rb.body == nil {
+		return nil
+	}
+
+	return rb.body
+}
 
 // Status returns the status code of the response.
 func (rb *ResponseBuilder) Status() int { return rb.status }
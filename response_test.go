@@ -35,3 +35,14 @@ func TestResponseBuilder_MultiWrite(t *testing.T) {
 	body, _ := io.ReadAll(res.Body())
 	require.Equal(t, "hello world", string(body))
 }
+
+func TestResponseBuilder_WriteCopiesInput(t *testing.T) {
+	res := NewResponse()
+
+	buf := []byte("hello")
+	res.Write(buf)
+	copy(buf, "HELLO")
+
+	body, _ := io.ReadAll(res.Body())
+	require.Equal(t, "hello", string(body))
+}
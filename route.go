@@ -1,7 +1,9 @@
 package medium
 
 import (
+	"fmt"
 	"net/http"
+	"regexp"
 	"strings"
 )
 
@@ -10,13 +12,85 @@ type requestable interface {
 }
 
 // A Route is a single route that can be matched against a request and holds a
-// reference to the handler used to handle the reques and holds a reference to
-// the handler used to handle the request.
+// reference to the handler used to handle the request.
+//
+// Route paths are made up of `/` delimited segments which can be:
+//
+//   - literal values, e.g. `/users`
+//   - named parameters that match a single segment, e.g. `/users/:id`
+//   - optional parameters that may be omitted, e.g. `/posts/:page?`
+//   - parameters embedded in a segment, e.g. `/files/:name.:ext`
+//   - constrained parameters, e.g. `/users/:id<int>` or `/:slug<[a-z-]+>`
+//   - a trailing catch-all that matches the rest of the path, e.g. `/assets/*path`
 type Route[C any] struct {
-	Method  string
-	Raw     string
-	parts   []string
-	handler HandlerFunc[C]
+	Method string
+	Raw    string
+	// Name is the name of the route provided by WithName, used to generate
+	// URLs via Router.URLFor.
+	Name string
+	// Metadata holds the values attached to the route via WithMetadata.
+	Metadata map[string]any
+	// variants holds the segments for each path the route matches. Routes
+	// have a single variant unless they contain optional segments.
+	variants [][]segment
+	handler  HandlerFunc[C]
+	limits   limits
+	// befores and middlewares hold the BeforeFuncs and middleware added via
+	// WithBefore and WithMiddleware.
+	befores     []BeforeFunc[C]
+	middlewares []Middleware
+}
+
+type segmentKind uint8
+
+const (
+	// segmentStatic matches a literal value.
+	segmentStatic segmentKind = iota
+	// segmentParam matches any value for a single segment.
+	segmentParam
+	// segmentPattern matches a single segment against a regular expression,
+	// capturing one or more parameters. Constrained and embedded parameters
+	// use pattern segments.
+	segmentPattern
+	// segmentCatchAll matches the remainder of the path.
+	segmentCatchAll
+)
+
+// segment represents a single `/` delimited part of a route path.
+type segment struct {
+	kind segmentKind
+	// value holds the literal value for static segments, the parameter name
+	// for param and catch-all segments, and the raw segment for patterns.
+	value string
+	// names and indexes hold the param names captured by pattern segments
+	// and the index of the submatch holding each value. literals holds the
+	// literal values surrounding each param, used to generate URLs.
+	names    []string
+	indexes  []int
+	literals []string
+	pattern  *regexp.Regexp
+}
+
+// match matches the segment against part, returning values with any captured
+// parameters appended.
+func (s segment) match(part string, values []string) ([]string, bool) {
+	switch s.kind {
+	case segmentStatic:
+		return values, s.value == part
+	case segmentPattern:
+		matches := s.pattern.FindStringSubmatch(part)
+		if matches == nil {
+			return values, false
+		}
+
+		for _, index := range s.indexes {
+			values = append(values, matches[index])
+		}
+
+		return values, true
+	default:
+		return append(values, part), true
+	}
 }
 
 // Given a request, returns true if the route matches the request and false if
@@ -26,26 +100,261 @@ func (r *Route[C]) IsMatch(req *RootRequest) (bool, map[string]string) {
 		return false, nil
 	}
 
-	reqParts := strings.Split(req.Request().URL.Path, "/")
-
-	if len(r.parts) != len(reqParts) {
+	path := req.Request().URL.Path
+	if !strings.HasPrefix(path, "/") {
 		return false, nil
 	}
 
-	params := make(map[string]string)
+	for _, segments := range r.variants {
+		if values, ok := matchSegments(segments, path[1:]); ok {
+			return true, paramsFor(segments, values)
+		}
+	}
+
+	return false, nil
+}
+
+// matchSegments matches path against segments, returning the captured
+// parameter values.
+func matchSegments(segments []segment, path string) ([]string, bool) {
+	values := make([]string, 0, len(segments))
+
+	for i, segment := range segments {
+		if segment.kind == segmentCatchAll {
+			return append(values, path), true
+		}
+
+		part, rest, last := nextSegment(path)
+
+		if last != (i == len(segments)-1) {
+			return nil, false
+		}
 
-	for i, part := range r.parts {
-		if strings.HasPrefix(part, ":") {
-			params[part[1:]] = reqParts[i]
-		} else if part != reqParts[i] {
-			return false, nil
+		var ok bool
+		if values, ok = segment.match(part, values); !ok {
+			return nil, false
 		}
+
+		path = rest
 	}
 
-	return true, params
+	return values, true
 }
 
 func newRoute[C any](method string, path string, handler HandlerFunc[C]) *Route[C] {
-	// TODO better support for `/`, remove double `//`
-	return &Route[C]{Method: method, Raw: path, parts: strings.Split(path, "/"), handler: handler}
+	return &Route[C]{Method: method, Raw: path, variants: parseSegments(path), handler: handler}
+}
+
+// parseSegments splits the given path into segments, ignoring the leading
+// `/`. A set of segments is returned for each combination of optional
+// segments being present or omitted.
+//
+// parseSegments panics if the path is invalid, e.g. when a catch-all is not
+// the final segment.
+func parseSegments(path string) [][]segment {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	variants := [][]segment{make([]segment, 0, len(parts))}
+
+	for i, part := range parts {
+		optional := strings.HasPrefix(part, ":") && strings.HasSuffix(part, "?")
+		if optional {
+			part = strings.TrimSuffix(part, "?")
+		}
+
+		parsed := parseSegment(part)
+
+		if parsed.kind == segmentCatchAll && i != len(parts)-1 {
+			panic(fmt.Sprintf("catch-all must be the final segment in route %s", path))
+		}
+
+		count := len(variants)
+		for j := 0; j < count; j++ {
+			if optional {
+				omitted := make([]segment, len(variants[j]))
+				copy(omitted, variants[j])
+				variants = append(variants, omitted)
+			}
+
+			variants[j] = append(variants[j], parsed)
+		}
+	}
+
+	for i, segments := range variants {
+		// Routes made up of only omitted segments match the root path.
+		if len(segments) == 0 {
+			variants[i] = []segment{{kind: segmentStatic}}
+		}
+	}
+
+	return variants
+}
+
+// constraints holds the named constraints that can be used in place of a
+// regular expression in route params, e.g. `:id<int>`.
+var constraints = map[string]string{
+	"int":  `-?[0-9]+`,
+	"uuid": `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+}
+
+// parseSegment parses a single path segment.
+func parseSegment(part string) segment {
+	switch {
+	case strings.HasPrefix(part, "*"):
+		name := part[1:]
+		if name == "" {
+			name = "*"
+		}
+
+		return segment{kind: segmentCatchAll, value: name}
+	case strings.HasPrefix(part, ":") && isParamName(part[1:]):
+		return segment{kind: segmentParam, value: part[1:]}
+	case strings.Contains(part, ":"):
+		return parsePatternSegment(part)
+	default:
+		return segment{kind: segmentStatic, value: part}
+	}
+}
+
+// parsePatternSegment parses a segment containing constrained params, e.g.
+// `:id<int>` or `:slug<[a-z-]+>`, or params embedded alongside literal values,
+// e.g. `:name.:ext` or `v:version`. Each unconstrained param matches as much
+// of the segment as possible while still allowing the rest of the segment to
+// match.
+func parsePatternSegment(part string) segment {
+	var names []string
+	var expr strings.Builder
+	expr.WriteString("^")
+
+	groups := 0
+	indexes := make([]int, 0)
+	literals := make([]string, 0)
+	literalStart := 0
+
+	for i := 0; i < len(part); {
+		if part[i] != ':' || i+1 == len(part) || !isParamNameChar(part[i+1]) {
+			i++
+			continue
+		}
+
+		expr.WriteString(regexp.QuoteMeta(part[literalStart:i]))
+		literals = append(literals, part[literalStart:i])
+
+		end := i + 1
+		for end < len(part) && isParamNameChar(part[end]) {
+			end++
+		}
+		names = append(names, part[i+1:end])
+
+		constraint := ".+"
+		if end < len(part) && part[end] == '<' {
+			closing := constraintEnd(part, end)
+			if closing == -1 {
+				panic(fmt.Sprintf("unterminated constraint in route segment %s", part))
+			}
+
+			constraint = part[end+1 : closing]
+			if named, ok := constraints[constraint]; ok {
+				constraint = named
+			}
+
+			end = closing + 1
+		}
+
+		compiled, err := regexp.Compile(constraint)
+		if err != nil {
+			panic(fmt.Sprintf("invalid constraint in route segment %s: %s", part, err))
+		}
+
+		indexes = append(indexes, groups+1)
+		groups += compiled.NumSubexp() + 1
+
+		expr.WriteString("(")
+		expr.WriteString(constraint)
+		expr.WriteString(")")
+
+		i = end
+		literalStart = end
+	}
+
+	expr.WriteString(regexp.QuoteMeta(part[literalStart:]))
+	expr.WriteString("$")
+	literals = append(literals, part[literalStart:])
+
+	return segment{
+		kind:     segmentPattern,
+		value:    part,
+		names:    names,
+		indexes:  indexes,
+		literals: literals,
+		pattern:  regexp.MustCompile(expr.String()),
+	}
+}
+
+// constraintEnd returns the index of the `>` closing the constraint that
+// starts at start, allowing for nested `<` and `>` in the constraint.
+func constraintEnd(part string, start int) int {
+	depth := 0
+
+	for i := start; i < len(part); i++ {
+		switch part[i] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// isParamName reports whether name is made up of only param name characters,
+// meaning a segment of `:name` is a param that spans the whole segment.
+func isParamName(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if !isParamNameChar(name[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isParamNameChar(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// paramsFor maps the matched values to the parameter names of the given
+// segments, in order.
+func paramsFor(segments []segment, values []string) map[string]string {
+	params := make(map[string]string, len(values))
+
+	i := 0
+	for _, segment := range segments {
+		switch segment.kind {
+		case segmentParam, segmentCatchAll:
+			params[segment.value] = values[i]
+			i++
+		case segmentPattern:
+			for _, name := range segment.names {
+				params[name] = values[i]
+				i++
+			}
+		}
+	}
+
+	return params
+}
+
+// nextSegment returns the first segment of path, the remainder of path after
+// the segment's trailing `/`, and whether the returned segment was the last.
+func nextSegment(path string) (string, string, bool) {
+	i := strings.IndexByte(path, '/')
+	if i == -1 {
+		return path, "", true
+	}
+
+	return path[:i], path[i+1:], false
 }
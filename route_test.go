@@ -89,6 +89,150 @@ func TestRouteMatching(t *testing.T) {
 			want:        true,
 			params:      map[string]string{"name": "greg", "location": "boston"},
 		},
+		"catch-all": {
+			reqMethod:   "GET",
+			reqPath:     "/assets/js/app.js",
+			routeMethod: "GET",
+			routePath:   "/assets/*path",
+			want:        true,
+			params:      map[string]string{"path": "js/app.js"},
+		},
+		"empty catch-all": {
+			reqMethod:   "GET",
+			reqPath:     "/assets/",
+			routeMethod: "GET",
+			routePath:   "/assets/*path",
+			want:        true,
+			params:      map[string]string{"path": ""},
+		},
+		"catch-all requires prefix": {
+			reqMethod:   "GET",
+			reqPath:     "/assets",
+			routeMethod: "GET",
+			routePath:   "/assets/*path",
+			want:        false,
+			params:      nil,
+		},
+		"optional present": {
+			reqMethod:   "GET",
+			reqPath:     "/posts/2",
+			routeMethod: "GET",
+			routePath:   "/posts/:page?",
+			want:        true,
+			params:      map[string]string{"page": "2"},
+		},
+		"optional omitted": {
+			reqMethod:   "GET",
+			reqPath:     "/posts",
+			routeMethod: "GET",
+			routePath:   "/posts/:page?",
+			want:        true,
+			params:      map[string]string{},
+		},
+		"optional in the middle": {
+			reqMethod:   "GET",
+			reqPath:     "/docs/intro",
+			routeMethod: "GET",
+			routePath:   "/:lang?/docs/:page",
+			want:        true,
+			params:      map[string]string{"page": "intro"},
+		},
+		"embedded params": {
+			reqMethod:   "GET",
+			reqPath:     "/files/archive.tar.gz",
+			routeMethod: "GET",
+			routePath:   "/files/:name.:ext",
+			want:        true,
+			params:      map[string]string{"name": "archive.tar", "ext": "gz"},
+		},
+		"embedded param with suffix": {
+			reqMethod:   "GET",
+			reqPath:     "/feeds/5.json",
+			routeMethod: "GET",
+			routePath:   "/feeds/:id.json",
+			want:        true,
+			params:      map[string]string{"id": "5"},
+		},
+		"embedded param with suffix mismatch": {
+			reqMethod:   "GET",
+			reqPath:     "/feeds/5.xml",
+			routeMethod: "GET",
+			routePath:   "/feeds/:id.json",
+			want:        false,
+			params:      nil,
+		},
+		"embedded param with multi-part suffix": {
+			reqMethod:   "GET",
+			reqPath:     "/downloads/medium-1.0.tar.gz",
+			routeMethod: "GET",
+			routePath:   "/downloads/:name.tar.gz",
+			want:        true,
+			params:      map[string]string{"name": "medium-1.0"},
+		},
+		"embedded params with prefix": {
+			reqMethod:   "GET",
+			reqPath:     "/api/v2/users",
+			routeMethod: "GET",
+			routePath:   "/api/v:version/users",
+			want:        true,
+			params:      map[string]string{"version": "2"},
+		},
+		"int constraint": {
+			reqMethod:   "GET",
+			reqPath:     "/users/42",
+			routeMethod: "GET",
+			routePath:   "/users/:id<int>",
+			want:        true,
+			params:      map[string]string{"id": "42"},
+		},
+		"int constraint mismatch": {
+			reqMethod:   "GET",
+			reqPath:     "/users/fox",
+			routeMethod: "GET",
+			routePath:   "/users/:id<int>",
+			want:        false,
+			params:      nil,
+		},
+		"regexp constraint": {
+			reqMethod:   "GET",
+			reqPath:     "/posts/hello-world",
+			routeMethod: "GET",
+			routePath:   "/posts/:slug<[a-z-]+>",
+			want:        true,
+			params:      map[string]string{"slug": "hello-world"},
+		},
+		"regexp constraint with groups": {
+			reqMethod:   "GET",
+			reqPath:     "/posts/ab.json",
+			routeMethod: "GET",
+			routePath:   "/posts/:slug<(a|b)+>.:format<(?:json|xml)>",
+			want:        true,
+			params:      map[string]string{"slug": "ab", "format": "json"},
+		},
+		"uuid constraint": {
+			reqMethod:   "GET",
+			reqPath:     "/teams/f47ac10b-58cc-4372-a567-0e02b2c3d479",
+			routeMethod: "GET",
+			routePath:   "/teams/:uuid<uuid>",
+			want:        true,
+			params:      map[string]string{"uuid": "f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+		},
+		"optional constraint": {
+			reqMethod:   "GET",
+			reqPath:     "/posts",
+			routeMethod: "GET",
+			routePath:   "/posts/:page<int>?",
+			want:        true,
+			params:      map[string]string{},
+		},
+		"embedded params mismatch": {
+			reqMethod:   "GET",
+			reqPath:     "/files/README",
+			routeMethod: "GET",
+			routePath:   "/files/:name.:ext",
+			want:        false,
+			params:      nil,
+		},
 	}
 
 	for name, tc := range tests {
@@ -106,3 +250,18 @@ func TestRouteMatching(t *testing.T) {
 		})
 	}
 }
+
+func TestRoute_InvalidConstraint(t *testing.T) {
+	handler := func(context.Context, *Request[NoData]) Response { return OK() }
+
+	assert.Panics(t, func() { newRoute("GET", "/users/:id<[a-z>", handler) })
+	assert.Panics(t, func() { newRoute("GET", "/users/:id<int", handler) })
+}
+
+func TestRoute_CatchAllMustBeLast(t *testing.T) {
+	assert.Panics(t, func() {
+		newRoute("GET", "/files/*path/edit", func(context.Context, *Request[NoData]) Response {
+			return OK()
+		})
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // Middleware is a function that is called before the action is executed.
@@ -17,19 +19,38 @@ type HandlerFunc[T any] func(context.Context, *Request[T]) Response
 // BeforeFunc is a function that is called before the action is executed.
 type BeforeFunc[T any] (func(ctx context.Context, req *Request[T], next Next) Response)
 
+// AfterFunc is a function that is called after the handler with the final
+// Response, which it can inspect or replace by returning a different Response.
+type AfterFunc[T any] (func(ctx context.Context, req *Request[T], res Response) Response)
+
+// AroundFunc is a function that wraps the BeforeFuncs, handler, and AfterFuncs
+// of a group. It must call next to continue the request and return the
+// Response returned by next, or a replacement.
+type AroundFunc[T any] (func(ctx context.Context, req *Request[T], next Next) Response)
+
 // Next is a function that calls the next BeforeFunc or HandlerFunc in the
 // chain. It accepts a context and returns a Response.
 type Next func(ctx context.Context) Response
 
 // routeable is used to ensure parity between RouteGroup and Router
 type routable[ParentData any, Data any] interface {
-	Match(method string, path string, handler HandlerFunc[Data])
-	Get(path string, handler HandlerFunc[Data])
-	Post(path string, handler HandlerFunc[Data])
-	Put(path string, handler HandlerFunc[Data])
-	Patch(path string, handler HandlerFunc[Data])
-	Delete(path string, handler HandlerFunc[Data])
+	Match(method string, path string, handler HandlerFunc[Data], opts ...RouteOption)
+	Get(path string, handler HandlerFunc[Data], opts ...RouteOption)
+	Post(path string, handler HandlerFunc[Data], opts ...RouteOption)
+	Put(path string, handler HandlerFunc[Data], opts ...RouteOption)
+	Patch(path string, handler HandlerFunc[Data], opts ...RouteOption)
+	Delete(path string, handler HandlerFunc[Data], opts ...RouteOption)
+	Head(path string, handler HandlerFunc[Data], opts ...RouteOption)
+	Options(path string, handler HandlerFunc[Data], opts ...RouteOption)
+	Mount(prefix string, handler http.Handler, opts ...RouteOption)
 	Before(before BeforeFunc[Data])
+	After(after AfterFunc[Data])
+	Around(around AroundFunc[Data])
+	ErrorHandler(handler ErrorHandler[Data])
+	Use(middleware Middleware)
+	MaxBodySize(n int64)
+	ReadTimeout(timeout time.Duration)
+	Timeout(timeout time.Duration)
 }
 
 var _ routable[NoData, NoData] = (*RouteGroup[NoData, NoData])(nil)
@@ -41,120 +62,189 @@ var _ routable[NoData, NoData] = (*Router[NoData])(nil)
 // Router is a collection of Routes and is used to dispatch requests to the
 // correct Route handler.
 type Router[T any] struct {
-	middlewares  []Middleware
-	routeGroup   *RouteGroup[NoData, T]
-	missingRoute HandlerFunc[T]
+	middlewares      []Middleware
+	routeGroup       *RouteGroup[NoData, T]
+	missingRoute     HandlerFunc[T]
+	methodNotAllowed HandlerFunc[T]
+	timedOutHandler  HandlerFunc[T]
 }
 
 // Creates a new Router with the given action creator used to create the application's root type.
 func New[T any](dataCreator func(*RootRequest) T) *Router[T] {
-	return &Router[T]{
-		routeGroup: &RouteGroup[NoData, T]{
-			routes: make([]*Route[T], 0),
-			dataCreator: func(ctx context.Context, r *RootRequest) (context.Context, T) {
-				return ctx, dataCreator(r)
-			},
-		},
-	}
+	return NewWithContext(func(ctx context.Context, r *RootRequest) (context.Context, T) {
+		return ctx, dataCreator(r)
+	})
 }
 
 // NewWithContext behaves the same as New, but is passed a context and expects
 // a context to be returned from the data creator.
 func NewWithContext[T any](dataCreator func(context.Context, *RootRequest) (context.Context, T)) *Router[T] {
-	return &Router[T]{
+	router := &Router[T]{
 		routeGroup: &RouteGroup[NoData, T]{
-			routes: make([]*Route[T], 0),
-			dataCreator: func(ctx context.Context, r *RootRequest) (context.Context, T) {
-				return dataCreator(ctx, r)
-			},
+			routes:      make([]*Route[T], 0),
+			dataCreator: dataCreator,
 		},
 	}
+	router.routeGroup.tree = newRouteTable(router.routeGroup.routeEntries)
+
+	return router
 }
 
 func (router *Router[T]) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
-	var handler http.HandlerFunc
+	r = withRouteDataHolder(r)
 
-	handler = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-		rootRequest := &RootRequest{originalRequest: r}
-		ok, routeData, routeHandler := router.routeGroup.dispatch(rootRequest)
+	handler := func(rw http.ResponseWriter, r *http.Request) {
+		entry, routeData := router.routeGroup.tree.lookup(r.Method, r.Host, r.URL.Path)
+		recordRouteData(r.Context(), routeData)
 
-		ctx, data := router.routeGroup.dataCreator(r.Context(), rootRequest)
-		newReq := NewRequest(rootRequest.originalRequest, data, routeData)
+		if entry == nil {
+			defer removeMultipartFiles(r)
+			WriteResponse(rw, r, router.unmatched(&RootRequest{originalRequest: r}))
 
-		var mediumHandler func(context.Context) Response
+			return
+		}
 
-		if !ok {
-			mediumHandler = func(ctx context.Context) Response {
-				if router.missingRoute == nil {
-					return StringResponse(http.StatusNotFound, "404 not found")
-				}
+		dispatch := func(rw http.ResponseWriter, r *http.Request) {
+			res, ok, done := effectiveLimits(entry.limits).serve(rw, r, func(r *http.Request) Response {
+				return entry.handler(r.Context(), &RootRequest{originalRequest: r, routeData: routeData})
+			})
+			defer done()
 
-				return router.missingRoute(
-					ctx,
-					newReq,
-				)
+			if !ok {
+				res = router.timedOut(&RootRequest{originalRequest: r, routeData: routeData})
 			}
-		} else {
-			mediumHandler = func(ctx context.Context) Response {
-				return routeHandler(ctx, rootRequest)
+
+			if errRes, ok := res.(*errorResponse); ok {
+				logHandlerError(r.Context(), errRes.err)
 			}
+
+			WriteResponse(rw, r, res)
 		}
 
-		res := mediumHandler(ctx)
+		withMiddleware(dispatch, entry.middlewares)(rw, r)
+	}
 
-		for key, values := range res.Header() {
-			for _, value := range values {
-				rw.Header().Add(key, value)
-			}
+	withMiddleware(handler, router.middlewares)(rw, r)
+}
+
+// withMiddleware returns a handler that calls middlewares, in order, before
+// calling handler.
+func withMiddleware(handler http.HandlerFunc, middlewares []Middleware) http.HandlerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		middleware := middlewares[i]
+		nextHandler := handler
+
+		handler = func(rw http.ResponseWriter, r *http.Request) {
+			middleware(rw, r, nextHandler)
 		}
-		if res := res.Status(); res != 0 {
-			rw.WriteHeader(res)
+	}
+
+	return handler
+}
+
+// WriteResponse writes res to rw, allowing middleware to respond with a
+// Response.
+func WriteResponse(rw http.ResponseWriter, r *http.Request, res Response) {
+	for key, values := range res.Header() {
+		for _, value := range values {
+			rw.Header().Add(key, value)
 		}
-		if res.Body() != nil {
-			io.Copy(rw, res.Body())
+	}
+
+	if res, ok := res.(writerResponse); ok {
+		res.writeResponse(rw, r)
+		return
+	}
+
+	if res := res.Status(); res != 0 {
+		rw.WriteHeader(res)
+	}
+	if res.Body() != nil && r.Method != http.MethodHead {
+		io.Copy(rw, res.Body())
+	}
+}
+
+// unmatched returns the response for a request that did not match a route. If
+// the path matches a route registered for another method, OPTIONS requests
+// are answered with the methods that can be used and other requests are
+// returned a 405 with the Allow header set. Otherwise a 404 is returned.
+func (router *Router[T]) unmatched(rootRequest *RootRequest) Response {
+	r := rootRequest.Request()
+	ctx, data := router.routeGroup.dataCreator(r.Context(), rootRequest)
+	req := NewRequest(r, data, nil)
+
+	allowed := router.routeGroup.tree.allowedMethods(r.Host, r.URL.Path)
+	if len(allowed) == 0 {
+		if router.missingRoute == nil {
+			return StringResponse(http.StatusNotFound, "404 not found")
 		}
-	})
 
-	for i := len(router.middlewares) - 1; i >= 0; i-- {
-		middleware := router.middlewares[i]
-		nextHandler := handler
+		return router.routeGroup.renderer.resolve(ctx, req, router.missingRoute(ctx, req))
+	}
 
-		handler = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-			middleware(rw, r, nextHandler)
-		})
+	if r.Method == http.MethodOptions {
+		res := NewResponse()
+		res.WriteStatus(http.StatusNoContent)
+		res.Header().Set("Allow", strings.Join(allowed, ", "))
+
+		return res
+	}
+
+	var res Response
+	if router.methodNotAllowed == nil {
+		res = StringResponse(http.StatusMethodNotAllowed, "405 method not allowed")
+	} else {
+		res = router.routeGroup.renderer.resolve(ctx, req, router.methodNotAllowed(ctx, req))
 	}
 
-	handler.ServeHTTP(rw, r)
+	if res.Header().Get("Allow") == "" {
+		res.Header().Set("Allow", strings.Join(allowed, ", "))
+	}
+
+	return res
 }
 
-// Match is used to add a new Route to the Router
-func (r *Router[T]) Match(method string, path string, handler HandlerFunc[T]) {
-	r.routeGroup.Match(method, path, handler)
+// Match is used to add a new Route to the Router. The route can be configured
+// with RouteOptions, e.g. WithName.
+func (r *Router[T]) Match(method string, path string, handler HandlerFunc[T], opts ...RouteOption) {
+	r.routeGroup.Match(method, path, handler, opts...)
 }
 
 // Defines a new Route that responds to GET requests.
-func (r *Router[T]) Get(path string, handler HandlerFunc[T]) {
-	r.Match(http.MethodGet, path, handler)
+func (r *Router[T]) Get(path string, handler HandlerFunc[T], opts ...RouteOption) {
+	r.Match(http.MethodGet, path, handler, opts...)
 }
 
 // Defines a new Route that responds to POST requests.
-func (r *Router[T]) Post(path string, handler HandlerFunc[T]) {
-	r.Match(http.MethodPost, path, handler)
+func (r *Router[T]) Post(path string, handler HandlerFunc[T], opts ...RouteOption) {
+	r.Match(http.MethodPost, path, handler, opts...)
 }
 
 // Defines a new Route that responds to PUT requests.
-func (r *Router[T]) Put(path string, handler HandlerFunc[T]) {
-	r.Match(http.MethodPut, path, handler)
+func (r *Router[T]) Put(path string, handler HandlerFunc[T], opts ...RouteOption) {
+	r.Match(http.MethodPut, path, handler, opts...)
 }
 
 // Defines a new Route that responds to PATCH requests.
-func (r *Router[T]) Patch(path string, handler HandlerFunc[T]) {
-	r.Match(http.MethodPatch, path, handler)
+func (r *Router[T]) Patch(path string, handler HandlerFunc[T], opts ...RouteOption) {
+	r.Match(http.MethodPatch, path, handler, opts...)
 }
 
 // Defines a new Route that responds to DELETE requests.
-func (r *Router[T]) Delete(path string, handler HandlerFunc[T]) {
-	r.Match(http.MethodDelete, path, handler)
+func (r *Router[T]) Delete(path string, handler HandlerFunc[T], opts ...RouteOption) {
+	r.Match(http.MethodDelete, path, handler, opts...)
+}
+
+// Defines a new Route that responds to HEAD requests. HEAD requests are
+// handled by GET routes when no HEAD route is defined.
+func (r *Router[T]) Head(path string, handler HandlerFunc[T], opts ...RouteOption) {
+	r.Match(http.MethodHead, path, handler, opts...)
+}
+
+// Defines a new Route that responds to OPTIONS requests. OPTIONS requests are
+// answered with the allowed methods when no OPTIONS route is defined.
+func (r *Router[T]) Options(path string, handler HandlerFunc[T], opts ...RouteOption) {
+	r.Match(http.MethodOptions, path, handler, opts...)
 }
 
 // Defines a handler that is called when no route matches the request.
@@ -162,6 +252,53 @@ func (r *Router[T]) Missing(handler HandlerFunc[T]) {
 	r.missingRoute = handler
 }
 
+// Defines a handler that is called when a route matches the request path but
+// not the request method. The Allow header is set on the returned response
+// unless the handler sets it.
+func (r *Router[T]) MethodNotAllowed(handler HandlerFunc[T]) {
+	r.methodNotAllowed = handler
+}
+
+// MaxBodySize limits the size of request bodies to n bytes. Requests with
+// larger bodies receive a 413 Request Entity Too Large. Groups and routes can
+// override the limit. See RouteGroup.MaxBodySize for more information.
+func (r *Router[T]) MaxBodySize(n int64) {
+	r.routeGroup.MaxBodySize(n)
+}
+
+// ReadTimeout limits how long clients have to send the request body. Groups
+// and routes can override the timeout. See RouteGroup.ReadTimeout for more
+// information.
+func (r *Router[T]) ReadTimeout(timeout time.Duration) {
+	r.routeGroup.ReadTimeout(timeout)
+}
+
+// ErrorHandler sets the handler used to map errors returned by routes to a
+// Response. See RouteGroup.ErrorHandler for more information.
+func (r *Router[T]) ErrorHandler(handler ErrorHandler[T]) {
+	r.routeGroup.ErrorHandler(handler)
+}
+
+// Timeout limits how long handlers have to return a response. Groups and
+// routes can override the timeout. See RouteGroup.Timeout for more
+// information.
+func (r *Router[T]) Timeout(timeout time.Duration) {
+	r.routeGroup.Timeout(timeout)
+}
+
+// Defines a handler that is called when a route's handler doesn't return a
+// response before its timeout. By default a 503 Service Unavailable is
+// returned.
+func (r *Router[T]) TimedOut(handler HandlerFunc[T]) {
+	r.timedOutHandler = handler
+}
+
+// Renderer attaches the renderer to the router so that templates rendered by
+// its routes include the renderer's default data.
+func (r *Router[T]) Renderer(renderer *Renderer[T]) {
+	r.routeGroup.renderer = renderer
+}
+
 // Defines a new middleware that is called in each request before the matching
 // route is called, if one exists. Middleware are only passed a
 // router.BaseAction and not the application specific action. This is due to
@@ -172,6 +309,9 @@ func (r *Router[T]) Missing(handler HandlerFunc[T]) {
 //
 // Middleware is called in the order that they are added. Middleware must call
 // next in order to continue the request, otherwise the request is halted.
+//
+// Router middleware is called for every request, even those that don't match
+// a route. Use RouteGroup.Use or WithMiddleware to scope middleware to routes.
 func (r *Router[T]) Use(middleware Middleware) {
 	r.middlewares = append(r.middlewares, middleware)
 }
@@ -186,6 +326,22 @@ func (r *Router[T]) prefix() string {
 	return r.routeGroup.prefix()
 }
 
+func (r *Router[T]) table() *routeTable {
+	return r.routeGroup.table()
+}
+
 func (r *Router[T]) Before(before BeforeFunc[T]) {
 	r.routeGroup.Before(before)
 }
+
+// After adds an AfterFunc that is called with the Response of every route.
+// See RouteGroup.After for more information.
+func (r *Router[T]) After(after AfterFunc[T]) {
+	r.routeGroup.After(after)
+}
+
+// Around adds an AroundFunc that wraps every route. See RouteGroup.Around for
+// more information.
+func (r *Router[T]) Around(around AroundFunc[T]) {
+	r.routeGroup.Around(around)
+}
@@ -45,11 +45,13 @@ func TestGroup_RouteMethods(t *testing.T) {
 	testCases := map[string]struct {
 		method string
 	}{
-		"Get":    {method: http.MethodGet},
-		"Post":   {method: http.MethodPost},
-		"Put":    {method: http.MethodPut},
-		"Patch":  {method: http.MethodPatch},
-		"Delete": {method: http.MethodDelete},
+		"Get":     {method: http.MethodGet},
+		"Post":    {method: http.MethodPost},
+		"Put":     {method: http.MethodPut},
+		"Patch":   {method: http.MethodPatch},
+		"Delete":  {method: http.MethodDelete},
+		"Head":    {method: http.MethodHead},
+		"Options": {method: http.MethodOptions},
 	}
 	router := New(WithNoData)
 
@@ -121,6 +123,183 @@ func TestRouter_MissingRoute_WithHandler(t *testing.T) {
 	require.Equal(t, 404, rw.Result().StatusCode)
 }
 
+func TestRouter_MissingRoute_Param(t *testing.T) {
+	router := New(WithNoData)
+
+	router.Missing(func(ctx context.Context, r *Request[NoData]) Response {
+		return StringResponse(http.StatusNotFound, "missing "+r.Param("id"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/where/do/i/go", nil)
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusNotFound, rw.Code)
+	require.Equal(t, "missing ", rw.Body.String())
+}
+
+func TestRouter_MethodNotAllowed_NoHandler(t *testing.T) {
+	router := New(WithNoData)
+
+	router.Get("/users/:id", func(ctx context.Context, r *Request[NoData]) Response { return OK() })
+	router.Patch("/users/:id", func(ctx context.Context, r *Request[NoData]) Response { return OK() })
+	router.Delete("/users/new", func(ctx context.Context, r *Request[NoData]) Response { return OK() })
+
+	req := httptest.NewRequest(http.MethodPost, "/users/new", nil)
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+	require.Equal(t, "405 method not allowed", rw.Body.String())
+	require.Equal(t, "DELETE, GET, HEAD, OPTIONS, PATCH", rw.Header().Get("Allow"))
+}
+
+func TestRouter_MethodNotAllowed_WithHandler(t *testing.T) {
+	router := New(WithNoData)
+
+	router.MethodNotAllowed(func(ctx context.Context, r *Request[NoData]) Response {
+		return StringResponse(http.StatusMethodNotAllowed, "Sorry, try another method.")
+	})
+
+	group := SubRouter(router, "/admin", WithNoData)
+	group.Post("/users", func(ctx context.Context, r *Request[NoData]) Response { return OK() })
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+	require.Equal(t, "Sorry, try another method.", rw.Body.String())
+	require.Equal(t, "OPTIONS, POST", rw.Header().Get("Allow"))
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/teams", nil)
+	rw = httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func TestRouter_Head(t *testing.T) {
+	router := New(WithNoData)
+
+	router.Get("/hello", func(ctx context.Context, r *Request[NoData]) Response {
+		res := NewResponse()
+		res.Header().Set("x-method", r.Method())
+		res.WriteString("hello")
+
+		return res
+	})
+
+	req := httptest.NewRequest(http.MethodHead, "/hello", nil)
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "HEAD", rw.Header().Get("x-method"))
+	require.Equal(t, "", rw.Body.String())
+
+	router.Head("/hello", func(ctx context.Context, r *Request[NoData]) Response {
+		return StringResponse(http.StatusNoContent, "")
+	})
+
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusNoContent, rw.Code)
+}
+
+func TestRouter_Options(t *testing.T) {
+	router := New(WithNoData)
+
+	router.Get("/hello", func(ctx context.Context, r *Request[NoData]) Response { return OK() })
+	router.Post("/hello", func(ctx context.Context, r *Request[NoData]) Response { return OK() })
+
+	req := httptest.NewRequest(http.MethodOptions, "/hello", nil)
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusNoContent, rw.Code)
+	require.Equal(t, "GET, HEAD, OPTIONS, POST", rw.Header().Get("Allow"))
+
+	router.Options("/hello", func(ctx context.Context, r *Request[NoData]) Response {
+		res := NewResponse()
+		res.Header().Set("Allow", "GET")
+
+		return res
+	})
+
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "GET", rw.Header().Get("Allow"))
+
+	req = httptest.NewRequest(http.MethodOptions, "/missing", nil)
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func TestRouter_ParamConstraints(t *testing.T) {
+	router := New(WithNoData)
+
+	group := Group(router, func(r *Request[NoData]) MyData { return MyData{} })
+	group.Get("/users/:id<int>", func(ctx context.Context, r *Request[MyData]) Response {
+		id, err := r.ParamInt("id")
+		require.NoError(t, err)
+
+		return StringResponse(http.StatusOK, fmt.Sprintf("user %d", id))
+	})
+
+	teams := SubRouter(router, "/teams", WithNoData)
+	teams.Get("/:id<uuid>", func(ctx context.Context, r *Request[NoData]) Response {
+		id, err := r.ParamUUID("id")
+		require.NoError(t, err)
+
+		return StringResponse(http.StatusOK, "team "+id.String())
+	})
+
+	router.Get("/users/:name", func(ctx context.Context, r *Request[NoData]) Response {
+		_, err := r.ParamInt("name")
+		require.Error(t, err)
+
+		_, err = r.ParamInt("missing")
+		require.ErrorIs(t, err, ErrMissingParam)
+
+		return StringResponse(http.StatusOK, "name "+r.Param("name"))
+	})
+
+	testCases := map[string]struct {
+		path string
+		code int
+		body string
+	}{
+		"int":                {path: "/users/42", code: http.StatusOK, body: "user 42"},
+		"falls through":      {path: "/users/fox", code: http.StatusOK, body: "name fox"},
+		"uuid":               {path: "/teams/F47AC10B-58CC-4372-A567-0E02B2C3D479", code: http.StatusOK, body: "team f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+		"uuid falls through": {path: "/teams/not-a-uuid", code: http.StatusNotFound, body: "404 not found"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			rw := httptest.NewRecorder()
+
+			router.ServeHTTP(rw, req)
+
+			require.Equal(t, tc.code, rw.Code)
+			require.Equal(t, tc.body, rw.Body.String())
+		})
+	}
+}
+
 func TestCustomActionType(t *testing.T) {
 	router := New[*MyData](func(rootRequest *RootRequest) *MyData {
 		return &MyData{Value: 1}
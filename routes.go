@@ -0,0 +1,108 @@
+package medium
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// RouteInfo describes a route registered on a Router or one of its groups.
+type RouteInfo struct {
+	// Method is the HTTP method the route responds to.
+	Method string `json:"method"`
+	// Pattern is the full path pattern of the route, including the prefixes of
+	// any subrouters it was defined on.
+	Pattern string `json:"pattern"`
+	// Name is the name of the route provided by WithName, if any.
+	Name string `json:"name,omitempty"`
+	// Group identifies the group the route was defined on. Routes defined on
+	// the router belong to the "root" group and nested groups are identified
+	// by the order they were registered on their parent, e.g. "root.0.1".
+	Group string `json:"group"`
+	// Host is the host pattern the route is restricted to by HostRouter, if
+	// any.
+	Host string `json:"host,omitempty"`
+	// GroupPrefix is the path prefix of the group the route was defined on.
+	GroupPrefix string `json:"group_prefix"`
+	// DataType is the type of the request data passed to the route's handler.
+	DataType string `json:"data_type"`
+	// Befores is the number of BeforeFuncs that are called before the route's
+	// handler, including those defined on parent groups and via WithBefore.
+	Befores int `json:"befores"`
+	// Metadata holds the values attached to the route via WithMetadata.
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// RouteList is a list of routes returned by Router.Routes.
+type RouteList []RouteInfo
+
+// Routes returns every route registered on the router and its groups, in the
+// order they are considered when dispatching requests.
+func (r *Router[T]) Routes() RouteList {
+	return r.routeGroup.describe("root", "", 0)
+}
+
+// describe implements dispatchable and returns the routes of the group and
+// its subgroups.
+func (g *RouteGroup[ParentData, Data]) describe(id string, host string, befores int) RouteList {
+	befores += len(g.befores)
+	if g.host != nil {
+		host = g.host.raw
+	}
+
+	dataType := fmt.Sprintf("%T", *new(Data))
+
+	routes := make(RouteList, 0, len(g.routes))
+	for _, route := range g.routes {
+		routes = append(routes, RouteInfo{
+			Method:      route.Method,
+			Pattern:     route.Raw,
+			Name:        route.Name,
+			Group:       id,
+			Host:        host,
+			GroupPrefix: g.routePrefix,
+			DataType:    dataType,
+			Befores:     befores + len(route.befores),
+			Metadata:    route.Metadata,
+		})
+	}
+
+	for i, group := range g.subgroups {
+		routes = append(routes, group.describe(id+"."+strconv.Itoa(i), host, befores)...)
+	}
+
+	return routes
+}
+
+// WriteTable writes the routes to w as a human readable table.
+func (rl RouteList) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tHOST\tPATTERN\tNAME\tGROUP\tPREFIX\tDATA\tBEFORES")
+
+	for _, route := range rl {
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
+			route.Method,
+			route.Host,
+			route.Pattern,
+			route.Name,
+			route.Group,
+			route.GroupPrefix,
+			route.DataType,
+			route.Befores,
+		)
+	}
+
+	return tw.Flush()
+}
+
+// WriteJSON writes the routes to w as a JSON array.
+func (rl RouteList) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(rl)
+}
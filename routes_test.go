@@ -0,0 +1,58 @@
+package medium
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_Routes(t *testing.T) {
+	handler := func(ctx context.Context, r *Request[NoData]) Response { return OK() }
+	before := func(ctx context.Context, r *Request[NoData], next Next) Response { return next(ctx) }
+
+	router := New(WithNoData)
+	router.Before(before)
+	router.Get("/", handler, WithName("root"))
+
+	group := Group(router, func(r *Request[NoData]) *MyData { return &MyData{} })
+	group.Before(func(ctx context.Context, r *Request[*MyData], next Next) Response { return next(ctx) })
+	group.Post("/sessions", func(ctx context.Context, r *Request[*MyData]) Response { return OK() })
+
+	teams := SubRouter(router, "/teams/:teamID", WithNoData)
+	teams.Before(before)
+	teams.Before(before)
+	teams.Delete("/", handler, WithName("team"))
+
+	api := HostRouter(router, "api.example.com", WithNoData)
+	api.Get("/status", handler)
+
+	expected := RouteList{
+		{Method: "GET", Pattern: "/", Name: "root", Group: "root", GroupPrefix: "", DataType: "medium.NoData", Befores: 1},
+		{Method: "POST", Pattern: "/sessions", Group: "root.0", GroupPrefix: "", DataType: "*medium.MyData", Befores: 2},
+		{Method: "DELETE", Pattern: "/teams/:teamID", Name: "team", Group: "root.1", GroupPrefix: "/teams/:teamID", DataType: "medium.NoData", Befores: 3},
+		{Method: "GET", Host: "api.example.com", Pattern: "/status", Group: "root.2", GroupPrefix: "", DataType: "medium.NoData", Befores: 1},
+	}
+
+	routes := router.Routes()
+	require.Equal(t, expected, routes)
+
+	var table bytes.Buffer
+	require.NoError(t, routes.WriteTable(&table))
+
+	lines := strings.Split(strings.TrimSpace(table.String()), "\n")
+	require.Len(t, lines, 5)
+	require.Equal(t, []string{"METHOD", "HOST", "PATTERN", "NAME", "GROUP", "PREFIX", "DATA", "BEFORES"}, strings.Fields(lines[0]))
+	require.Equal(t, []string{"DELETE", "/teams/:teamID", "team", "root.1", "/teams/:teamID", "medium.NoData", "3"}, strings.Fields(lines[3]))
+	require.Equal(t, []string{"GET", "api.example.com", "/status", "root.2", "medium.NoData", "1"}, strings.Fields(lines[4]))
+
+	var out bytes.Buffer
+	require.NoError(t, routes.WriteJSON(&out))
+
+	var decoded RouteList
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Equal(t, expected, decoded)
+}
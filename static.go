@@ -0,0 +1,298 @@
+package medium
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StaticConfig configures how files are served by StaticWithConfig.
+type StaticConfig struct {
+	// Index is the name of the file served for directory requests. Defaults
+	// to index.html.
+	Index string
+	// ListDirectories enables HTML directory listings for directories that do
+	// not contain an index file. Directories are not listed by default.
+	ListDirectories bool
+	// CacheControl is the Cache-Control header sent with files that are not
+	// fingerprinted. No header is sent if empty.
+	CacheControl string
+	// FingerprintCacheControl is the Cache-Control header sent with
+	// fingerprinted files. Defaults to caching the file for a year.
+	FingerprintCacheControl string
+	// IsFingerprinted reports whether the file with the given name includes a
+	// fingerprint of its contents, e.g. `app-5f2c9a1b.js`. Defaults to
+	// matching names containing a `-` or `.` followed by 8 or more hex
+	// characters before the extension.
+	IsFingerprinted func(name string) bool
+	// Precompressed enables serving `.br` and `.gz` siblings of a file when
+	// the client accepts brotli or gzip encoding.
+	Precompressed bool
+}
+
+var fingerprintPattern = regexp.MustCompile(`[-.][0-9a-fA-F]{8,}\.[^/]+$`)
+
+// Static serves the files in fsys under the given prefix using the default
+// StaticConfig. See StaticWithConfig for more information.
+func (g *RouteGroup[ParentData, Data]) Static(prefix string, fsys fs.FS) {
+	g.StaticWithConfig(prefix, fsys, StaticConfig{})
+}
+
+// StaticWithConfig serves the files in fsys under the given prefix for GET and
+// HEAD requests. fsys can be any fs.FS, such as an embed.FS or os.DirFS.
+//
+// Files are served with an ETag based on their contents and respect
+// conditional and Range requests. Directory requests are redirected to
+// include a trailing slash and serve the directory's index file.
+func (g *RouteGroup[ParentData, Data]) StaticWithConfig(prefix string, fsys fs.FS, config StaticConfig) {
+	server := newStaticServer(fsys, config)
+
+	g.Get(prefix, func(ctx context.Context, r *Request[Data]) Response {
+		if strings.HasSuffix(r.URL().Path, "/") {
+			return server.serve(r.Request(), "")
+		}
+
+		return Redirect(r.URL().Path + "/")
+	})
+	g.Get(joinPath(prefix, "*"), func(ctx context.Context, r *Request[Data]) Response {
+		return server.serve(r.Request(), r.Params()["*"])
+	})
+}
+
+// Static serves the files in fsys under the given prefix. See
+// RouteGroup.StaticWithConfig for more information.
+func (r *Router[T]) Static(prefix string, fsys fs.FS) {
+	r.routeGroup.Static(prefix, fsys)
+}
+
+// StaticWithConfig serves the files in fsys under the given prefix. See
+// RouteGroup.StaticWithConfig for more information.
+func (r *Router[T]) StaticWithConfig(prefix string, fsys fs.FS, config StaticConfig) {
+	r.routeGroup.StaticWithConfig(prefix, fsys, config)
+}
+
+// staticServer serves files from an fs.FS, caching the ETag of each file.
+type staticServer struct {
+	fsys   fs.FS
+	config StaticConfig
+	etags  sync.Map
+}
+
+// etagKey identifies a version of a file, so that ETags are recomputed when
+// files served from disk change.
+type etagKey struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func newStaticServer(fsys fs.FS, config StaticConfig) *staticServer {
+	if config.Index == "" {
+		config.Index = "index.html"
+	}
+
+	if config.FingerprintCacheControl == "" {
+		config.FingerprintCacheControl = "public, max-age=31536000, immutable"
+	}
+
+	if config.IsFingerprinted == nil {
+		config.IsFingerprinted = fingerprintPattern.MatchString
+	}
+
+	return &staticServer{fsys: fsys, config: config}
+}
+
+// serve returns the response for the file with the given name, relative to
+// the root of the file system.
+func (s *staticServer) serve(r *http.Request, name string) Response {
+	isDir := name == "" || strings.HasSuffix(name, "/")
+
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		name = "."
+	}
+
+	if !fs.ValidPath(name) {
+		return StringResponse(http.StatusNotFound, "404 not found")
+	}
+
+	info, err := fs.Stat(s.fsys, name)
+	if err != nil {
+		return StringResponse(http.StatusNotFound, "404 not found")
+	}
+
+	if info.IsDir() {
+		if !isDir {
+			return Redirect(r.URL.Path + "/")
+		}
+
+		index := path.Join(name, s.config.Index)
+		if info, err := fs.Stat(s.fsys, index); err == nil && !info.IsDir() {
+			return &fileResponse{server: s, name: index, info: info, header: http.Header{}}
+		}
+
+		if s.config.ListDirectories {
+			return s.listDirectory(r, name)
+		}
+
+		return StringResponse(http.StatusNotFound, "404 not found")
+	}
+
+	return &fileResponse{server: s, name: name, info: info, header: http.Header{}}
+}
+
+// fileResponse writes a file using http.ServeContent so that conditional and
+// Range requests are handled. Precompressed siblings of the file are served
+// instead when enabled and accepted by the client.
+type fileResponse struct {
+	server *staticServer
+	name   string
+	info   fs.FileInfo
+	header http.Header
+}
+
+var _ writerResponse = (*fileResponse)(nil)
+
+func (fr *fileResponse) Status() int         { return http.StatusOK }
+func (fr *fileResponse) Header() http.Header { return fr.header }
+func (fr *fileResponse) Body() io.Reader     { return nil }
+
+func (fr *fileResponse) writeResponse(rw http.ResponseWriter, r *http.Request) {
+	s := fr.server
+	name, info := fr.name, fr.info
+	header := rw.Header()
+
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	if s.config.Precompressed {
+		header.Add("Vary", "Accept-Encoding")
+
+		for _, encoding := range []struct{ name, ext string }{{"br", ".br"}, {"gzip", ".gz"}} {
+			if !acceptsEncoding(r, encoding.name) {
+				continue
+			}
+
+			compressedInfo, err := fs.Stat(s.fsys, name+encoding.ext)
+			if err != nil || compressedInfo.IsDir() {
+				continue
+			}
+
+			if header.Get("Content-Type") == "" {
+				header.Set("Content-Type", "application/octet-stream")
+			}
+
+			header.Set("Content-Encoding", encoding.name)
+			name, info = name+encoding.ext, compressedInfo
+
+			break
+		}
+	}
+
+	if s.config.IsFingerprinted(fr.name) {
+		header.Set("Cache-Control", s.config.FingerprintCacheControl)
+	} else if s.config.CacheControl != "" {
+		header.Set("Cache-Control", s.config.CacheControl)
+	}
+
+	file, err := s.fsys.Open(name)
+	if err != nil {
+		http.Error(rw, "404 not found", http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			http.Error(rw, "500 internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		content = bytes.NewReader(data)
+	}
+
+	etag, err := s.etag(name, info, content)
+	if err != nil {
+		http.Error(rw, "500 internal server error", http.StatusInternalServerError)
+		return
+	}
+	header.Set("ETag", etag)
+
+	http.ServeContent(rw, r, name, info.ModTime(), content)
+}
+
+// etag returns the cached ETag for the file, computing it from the file's
+// contents if needed.
+func (s *staticServer) etag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	key := etagKey{name: name, size: info.Size(), modTime: info.ModTime()}
+	if etag, ok := s.etags.Load(key); ok {
+		return etag.(string), nil
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	s.etags.Store(key, etag)
+
+	return etag, nil
+}
+
+// listDirectory returns an HTML listing of the named directory.
+func (s *staticServer) listDirectory(r *http.Request, name string) Response {
+	entries, err := fs.ReadDir(s.fsys, name)
+	if err != nil {
+		return StringResponse(http.StatusInternalServerError, "500 internal server error")
+	}
+
+	res := NewResponse()
+	res.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	fmt.Fprintf(res, "<!doctype html>\n<title>%s</title>\n<ul>\n", html.EscapeString(r.URL.Path))
+	for _, entry := range entries {
+		entryName := entry.Name()
+		if entry.IsDir() {
+			entryName += "/"
+		}
+
+		link := url.URL{Path: entryName}
+		fmt.Fprintf(res, "<li><a href=\"%s\">%s</a></li>\n", html.EscapeString(link.String()), html.EscapeString(entryName))
+	}
+	fmt.Fprint(res, "</ul>\n")
+
+	return res
+}
+
+// acceptsEncoding reports whether the request's Accept-Encoding header
+// includes the given encoding with a non-zero quality.
+func acceptsEncoding(r *http.Request, encoding string) bool {
+	for _, accept := range parseAccept(r.Header.Values("Accept-Encoding")) {
+		if accept.value == strings.ToLower(encoding) {
+			return accept.q > 0
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,119 @@
+package medium
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/static
+var staticFS embed.FS
+
+func serveStatic(router http.Handler, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	return rw
+}
+
+func TestRouter_Static_EmbedFS(t *testing.T) {
+	files, err := fs.Sub(staticFS, "testdata/static")
+	require.NoError(t, err)
+
+	router := New(WithNoData)
+	router.Static("/assets", files)
+
+	rw := serveStatic(router, "/assets/style.css", nil)
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "body { color: red; }\n", rw.Body.String())
+	require.Equal(t, "text/css; charset=utf-8", rw.Header().Get("Content-Type"))
+	require.NotEmpty(t, rw.Header().Get("ETag"))
+	require.Empty(t, rw.Header().Get("Cache-Control"))
+
+	etag := rw.Header().Get("ETag")
+	rw = serveStatic(router, "/assets/style.css", http.Header{"If-None-Match": {etag}})
+	require.Equal(t, http.StatusNotModified, rw.Code)
+	require.Empty(t, rw.Body.String())
+
+	rw = serveStatic(router, "/assets/style.css", http.Header{"Range": {"bytes=0-3"}})
+	require.Equal(t, http.StatusPartialContent, rw.Code)
+	require.Equal(t, "body", rw.Body.String())
+
+	rw = serveStatic(router, "/assets/app-5f2c9a1b.js", nil)
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "public, max-age=31536000, immutable", rw.Header().Get("Cache-Control"))
+
+	rw = serveStatic(router, "/assets", nil)
+	require.Equal(t, http.StatusFound, rw.Code)
+	require.Equal(t, "/assets/", rw.Header().Get("Location"))
+
+	rw = serveStatic(router, "/assets/", nil)
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "<h1>home</h1>\n", rw.Body.String())
+
+	rw = serveStatic(router, "/assets/docs", nil)
+	require.Equal(t, http.StatusFound, rw.Code)
+	require.Equal(t, "/assets/docs/", rw.Header().Get("Location"))
+
+	rw = serveStatic(router, "/assets/docs/", nil)
+	require.Equal(t, http.StatusNotFound, rw.Code)
+
+	rw = serveStatic(router, "/assets/missing.txt", nil)
+	require.Equal(t, http.StatusNotFound, rw.Code)
+
+	rw = serveStatic(router, "/assets/../static_test.go", nil)
+	require.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func TestRouter_Static_Config(t *testing.T) {
+	modTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	files := fstest.MapFS{
+		"app.js":         {Data: []byte("plain"), ModTime: modTime},
+		"app.js.gz":      {Data: []byte("gzipped"), ModTime: modTime},
+		"app.js.br":      {Data: []byte("brotli"), ModTime: modTime},
+		"docs/intro.txt": {Data: []byte("intro"), ModTime: modTime},
+		"docs/a b.txt":   {Data: []byte("spaces"), ModTime: modTime},
+	}
+
+	router := New(WithNoData)
+	group := SubRouter(router, "/public", WithNoData)
+	group.StaticWithConfig("/", files, StaticConfig{
+		ListDirectories: true,
+		CacheControl:    "no-cache",
+		Precompressed:   true,
+	})
+
+	rw := serveStatic(router, "/public/app.js", nil)
+	require.Equal(t, "plain", rw.Body.String())
+	require.Equal(t, "no-cache", rw.Header().Get("Cache-Control"))
+	require.Equal(t, "Accept-Encoding", rw.Header().Get("Vary"))
+	require.Empty(t, rw.Header().Get("Content-Encoding"))
+
+	rw = serveStatic(router, "/public/app.js", http.Header{"Accept-Encoding": {"gzip, br"}})
+	require.Equal(t, "brotli", rw.Body.String())
+	require.Equal(t, "br", rw.Header().Get("Content-Encoding"))
+	require.Equal(t, "text/javascript; charset=utf-8", rw.Header().Get("Content-Type"))
+
+	rw = serveStatic(router, "/public/app.js", http.Header{"Accept-Encoding": {"gzip, br;q=0"}})
+	require.Equal(t, "gzipped", rw.Body.String())
+	require.Equal(t, "gzip", rw.Header().Get("Content-Encoding"))
+
+	rw = serveStatic(router, "/public/app.js", http.Header{"If-Modified-Since": {modTime.Format(http.TimeFormat)}})
+	require.Equal(t, http.StatusNotModified, rw.Code)
+
+	rw = serveStatic(router, "/public/docs/", nil)
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Contains(t, rw.Body.String(), `<a href="intro.txt">intro.txt</a>`)
+	require.Contains(t, rw.Body.String(), `<a href="a%20b.txt">a b.txt</a>`)
+}
@@ -0,0 +1,130 @@
+package medium
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/blakewilliams/medium/mlog"
+)
+
+// StreamFunc writes the body of a streaming response. The provided context is
+// canceled when the client disconnects, after which writes return the
+// context's error.
+type StreamFunc func(ctx context.Context, w *StreamWriter) error
+
+// StreamResponse is a Response that writes its body as it's generated instead
+// of buffering it. See Stream for more information.
+type StreamResponse struct {
+	ctx    context.Context
+	status int
+	header http.Header
+	fn     StreamFunc
+}
+
+var _ writerResponse = (*StreamResponse)(nil)
+
+// Stream returns a response that calls fn to write the response body once the
+// status and headers have been sent to the client. Data written by fn can be
+// sent to the client immediately by calling Flush, allowing large or slow
+// responses like CSV exports to be sent without buffering them in memory.
+//
+// Since no Content-Length is known ahead of time, HTTP/1.1 responses use
+// chunked transfer encoding.
+//
+// ctx should be the context passed to the handler so that values added by
+// BeforeFuncs are available to fn. Errors returned by fn are logged, since
+// the status has already been sent to the client.
+func Stream(ctx context.Context, status int, fn StreamFunc) *StreamResponse {
+	return &StreamResponse{ctx: ctx, status: status, header: http.Header{}, fn: fn}
+}
+
+// Status returns the status code of the response.
+func (sr *StreamResponse) Status() int { return sr.status }
+
+// Header returns the header map for the response. Headers must be set before
+// the response is returned from the handler.
+func (sr *StreamResponse) Header() http.Header { return sr.header }
+
+// Body returns nil since streaming responses write directly to the client.
+func (sr *StreamResponse) Body() io.Reader { return nil }
+
+func (sr *StreamResponse) writeResponse(rw http.ResponseWriter, r *http.Request) {
+	if sr.status != 0 {
+		rw.WriteHeader(sr.status)
+	}
+
+	w := newStreamWriter(sr.ctx, rw)
+	if err := w.Flush(); err != nil || r.Method == http.MethodHead {
+		return
+	}
+
+	err := sr.fn(sr.ctx, w)
+	if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		mlog.Error(sr.ctx, "error writing streaming response", mlog.Fields{"error": err})
+	}
+}
+
+// StreamWriter is passed to StreamFuncs and writes directly to the client.
+type StreamWriter struct {
+	ctx     context.Context
+	rw      http.ResponseWriter
+	flusher http.Flusher
+}
+
+var _ io.Writer = (*StreamWriter)(nil)
+
+func newStreamWriter(ctx context.Context, rw http.ResponseWriter) *StreamWriter {
+	// flusher is nil if flushing isn't supported.
+	flusher, _ := unwrapWriter[http.Flusher](rw)
+
+	return &StreamWriter{ctx: ctx, rw: rw, flusher: flusher}
+}
+
+// Write writes p to the client. Written data may be buffered until Flush is
+// called. If the context has been canceled, the context's error is returned.
+func (sw *StreamWriter) Write(p []byte) (int, error) {
+	if err := sw.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return sw.rw.Write(p)
+}
+
+// WriteString writes s to the client.
+func (sw *StreamWriter) WriteString(s string) (int, error) {
+	return sw.Write([]byte(s))
+}
+
+// Flush sends any buffered data to the client. If the context has been
+// canceled, the context's error is returned.
+func (sw *StreamWriter) Flush() error {
+	if err := sw.ctx.Err(); err != nil {
+		return err
+	}
+
+	if sw.flusher != nil {
+		sw.flusher.Flush()
+	}
+
+	return nil
+}
+
+// unwrapWriter returns rw as a T, unwrapping response writers wrapped by
+// middleware if needed. false is returned if no writer in the chain is a T.
+func unwrapWriter[T any](rw http.ResponseWriter) (T, bool) {
+	for {
+		if writer, ok := rw.(T); ok {
+			return writer, true
+		}
+
+		unwrapper, ok := rw.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			var zero T
+			return zero, false
+		}
+
+		rw = unwrapper.Unwrap()
+	}
+}
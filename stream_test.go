@@ -0,0 +1,98 @@
+package medium
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream(t *testing.T) {
+	router := New(WithNoData)
+	router.Get("/export.csv", func(ctx context.Context, r *Request[NoData]) Response {
+		res := Stream(ctx, http.StatusOK, func(ctx context.Context, w *StreamWriter) error {
+			for i := 1; i <= 3; i++ {
+				if _, err := fmt.Fprintf(w, "row,%d\n", i); err != nil {
+					return err
+				}
+			}
+
+			return w.Flush()
+		})
+		res.Header().Set("Content-Type", "text/csv")
+
+		return res
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/export.csv", nil)
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.True(t, rw.Flushed)
+	require.Equal(t, "text/csv", rw.Header().Get("Content-Type"))
+	require.Equal(t, "row,1\nrow,2\nrow,3\n", rw.Body.String())
+
+	req = httptest.NewRequest(http.MethodHead, "/export.csv", nil)
+	rw = httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Empty(t, rw.Body.String())
+}
+
+func TestStream_FlushesAndCancels(t *testing.T) {
+	written := make(chan struct{})
+	done := make(chan error, 1)
+
+	router := New(WithNoData)
+	router.Get("/events", func(ctx context.Context, r *Request[NoData]) Response {
+		return Stream(ctx, http.StatusAccepted, func(ctx context.Context, w *StreamWriter) error {
+			_, _ = w.WriteString("first\n")
+			_ = w.Flush()
+			close(written)
+
+			<-ctx.Done()
+			_, err := w.WriteString("second\n")
+			done <- err
+
+			return err
+		})
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	require.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusAccepted, res.StatusCode)
+	require.Equal(t, []string{"chunked"}, res.TransferEncoding)
+
+	line, err := bufio.NewReader(res.Body).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "first\n", line)
+	<-written
+
+	cancel()
+
+	select {
+	case err := <-done:
+		require.True(t, errors.Is(err, context.Canceled))
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream was not canceled")
+	}
+}
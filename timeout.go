@@ -0,0 +1,259 @@
+package medium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/blakewilliams/medium/mlog"
+)
+
+// Panic is the value re-panicked with when a handler run with a timeout
+// panics. Handlers with a timeout run in their own goroutine, so the panic is
+// recovered there and re-raised with a *Panic on the goroutine serving the
+// request, holding the stack of the handler's goroutine. Code that recovers
+// panics from routes with a timeout receives a *Panic instead of the value
+// passed to panic, which can be read via Value, or via errors.Is and
+// errors.As when it's an error.
+type Panic struct {
+	// Value is the value passed to panic.
+	Value any
+	// PCs holds the program counters of the panicking goroutine's stack, as
+	// returned by runtime.Callers.
+	PCs []uintptr
+}
+
+// newPanic returns a *Panic holding recovered and the stack of the goroutine
+// that panicked. It must be called directly from the deferred function that
+// recovered the panic. recovered is returned as is if it's already a *Panic
+// or is http.ErrAbortHandler, which must be re-raised unchanged.
+func newPanic(recovered any) any {
+	if _, ok := recovered.(*Panic); ok || recovered == http.ErrAbortHandler {
+		return recovered
+	}
+
+	pcs := make([]uintptr, 64)
+	// Skip runtime.Callers, newPanic and the deferred function.
+	pcs = pcs[:runtime.Callers(3, pcs)]
+
+	return &Panic{Value: recovered, PCs: pcs}
+}
+
+// Error returns the value passed to panic, formatted with %v.
+func (p *Panic) Error() string {
+	return fmt.Sprintf("%v", p.Value)
+}
+
+// Unwrap returns the value passed to panic if it's an error.
+func (p *Panic) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// timeoutResult holds the outcome of a handler run by serve.
+type timeoutResult struct {
+	res Response
+	// panicVal is the value to re-panic with if the handler panicked.
+	panicVal any
+}
+
+// serve calls handler with the limits enforced, returning the response and a
+// function that must be called once the response has been written. false is
+// returned if handler didn't return before the timeout.
+//
+// When a timeout is set, the body and read limits are still applied on the
+// calling goroutine, and only handler is called in a new goroutine with a copy
+// of r whose context is canceled once the timeout elapses. If the timeout
+// elapses first, the goroutine's response is discarded when handler returns,
+// so it can never be written after the timeout response.
+func (l limits) serve(rw http.ResponseWriter, r *http.Request, handler func(r *http.Request) Response) (Response, bool, func()) {
+	if l.timeout <= 0 {
+		res := l.apply(rw, r, func() Response { return handler(r) })
+
+		return res, true, func() { removeMultipartFiles(r) }
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), l.timeout)
+	r = r.WithContext(ctx)
+
+	ok := true
+	res := l.apply(rw, r, func() Response {
+		var res Response
+		res, ok = runWithTimeout(ctx, cancel, rw, r, handler)
+
+		return res
+	})
+
+	if !ok {
+		return nil, false, func() {}
+	}
+
+	return res, true, func() {
+		cancel()
+		removeMultipartFiles(r)
+	}
+}
+
+// runWithTimeout calls handler in a new goroutine, returning its response if
+// it returns before ctx's deadline. Otherwise false is returned and the
+// handler's response is discarded once it returns. r's body is closed to the
+// handler before returning false, so it isn't read after the request has
+// been served.
+func runWithTimeout(ctx context.Context, cancel context.CancelFunc, rw http.ResponseWriter, r *http.Request, handler func(r *http.Request) Response) (Response, bool) {
+	var body *timeoutBody
+	if r.Body != nil && r.Body != http.NoBody {
+		body = &timeoutBody{ReadCloser: r.Body}
+		r.Body = body
+	}
+
+	var mu sync.Mutex
+	timedOut := false
+	results := make(chan timeoutResult, 1)
+
+	go func() {
+		var result timeoutResult
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				result = timeoutResult{panicVal: newPanic(recovered)}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if !timedOut {
+				results <- result
+				return
+			}
+
+			discard(ctx, r, result)
+			cancel()
+		}()
+
+		result.res = handler(r)
+	}()
+
+	var result timeoutResult
+
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			// The client went away, so wait for the handler to notice the
+			// canceled context instead of responding with a timeout.
+			result = <-results
+			break
+		}
+
+		mu.Lock()
+		select {
+		case result = <-results:
+		default:
+			// The handler is still running, so it discards its result
+			// when it returns.
+			timedOut = true
+		}
+		mu.Unlock()
+
+		if timedOut {
+			body.stop(rw)
+			return nil, false
+		}
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		// The handler returned after the timeout elapsed, e.g. because it
+		// noticed the canceled context.
+		discard(ctx, r, result)
+		cancel()
+
+		return nil, false
+	}
+
+	if result.panicVal != nil {
+		// Re-panic on the serving goroutine so the panic can be rescued by
+		// middleware.
+		cancel()
+		removeMultipartFiles(r)
+		panic(result.panicVal)
+	}
+
+	return result.res, true
+}
+
+// timeoutBody is the request body passed to handlers run with a timeout. It's
+// closed to the handler once the timeout elapses, since the body can't be
+// read after the request has been served.
+type timeoutBody struct {
+	io.ReadCloser
+	mu     sync.Mutex
+	closed bool
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	return b.ReadCloser.Read(p)
+}
+
+func (b *timeoutBody) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+
+	return b.ReadCloser.Close()
+}
+
+// stop closes the body to the handler, waiting for any read in progress to
+// return. In progress reads are interrupted by setting the connection's read
+// deadline, if supported.
+func (b *timeoutBody) stop(rw http.ResponseWriter) {
+	if b == nil {
+		return
+	}
+
+	if !b.mu.TryLock() {
+		_ = http.NewResponseController(rw).SetReadDeadline(time.Now())
+		b.mu.Lock()
+	}
+	defer b.mu.Unlock()
+
+	b.closed = true
+}
+
+// discard cleans up after a handler that returned after its timeout elapsed,
+// logging the panic if it panicked.
+func discard(ctx context.Context, r *http.Request, result timeoutResult) {
+	if result.panicVal != nil {
+		mlog.Error(ctx, "handler panicked after timeout", mlog.Fields{"panic": fmt.Sprintf("%v", result.panicVal)})
+	}
+
+	removeMultipartFiles(r)
+}
+
+// timedOut returns the response for a request whose handler didn't return
+// before its timeout.
+func (router *Router[T]) timedOut(rootRequest *RootRequest) Response {
+	if router.timedOutHandler == nil {
+		return StringResponse(http.StatusServiceUnavailable, "503 service unavailable")
+	}
+
+	r := rootRequest.Request()
+	ctx, data := router.routeGroup.dataCreator(r.Context(), rootRequest)
+	req := NewRequest(r, data, rootRequest.routeData)
+
+	return router.routeGroup.renderer.resolve(ctx, req, router.timedOutHandler(ctx, req))
+}
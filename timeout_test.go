@@ -0,0 +1,161 @@
+package medium
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeout(t *testing.T) {
+	handlerErrs := make(chan error, 1)
+	slow := func(ctx context.Context, r *Request[NoData]) Response {
+		select {
+		case <-ctx.Done():
+			handlerErrs <- ctx.Err()
+			return StringResponse(http.StatusOK, "too late")
+		case <-time.After(time.Second):
+			return StringResponse(http.StatusOK, "slow")
+		}
+	}
+
+	router := New(WithNoData)
+	router.Timeout(20 * time.Millisecond)
+	router.Get("/slow", slow)
+	router.Get("/fast", func(ctx context.Context, r *Request[NoData]) Response {
+		return StringResponse(http.StatusOK, "fast")
+	})
+	router.Get("/unlimited", func(ctx context.Context, r *Request[NoData]) Response {
+		time.Sleep(40 * time.Millisecond)
+		return StringResponse(http.StatusOK, "unlimited")
+	}, WithTimeout(-1))
+
+	group := Group(router, WithNoData)
+	group.Timeout(time.Hour)
+	group.Get("/group", func(ctx context.Context, r *Request[NoData]) Response {
+		time.Sleep(40 * time.Millisecond)
+		return StringResponse(http.StatusOK, "group")
+	})
+
+	testCases := map[string]struct {
+		path   string
+		status int
+		body   string
+	}{
+		"timed out":             {path: "/slow", status: http.StatusServiceUnavailable, body: "503 service unavailable"},
+		"within timeout":        {path: "/fast", status: http.StatusOK, body: "fast"},
+		"route removes timeout": {path: "/unlimited", status: http.StatusOK, body: "unlimited"},
+		"group overrides":       {path: "/group", status: http.StatusOK, body: "group"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			rw := httptest.NewRecorder()
+
+			router.ServeHTTP(rw, req)
+
+			require.Equal(t, tc.status, rw.Code)
+			require.Equal(t, tc.body, rw.Body.String())
+		})
+	}
+
+	select {
+	case err := <-handlerErrs:
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+	case <-time.After(time.Second):
+		t.Fatal("handler context was not canceled")
+	}
+}
+
+func TestTimeout_TimedOutHandler(t *testing.T) {
+	router := New(func(r *RootRequest) string { return "data" })
+	router.Timeout(10 * time.Millisecond)
+	router.TimedOut(func(ctx context.Context, r *Request[string]) Response {
+		return StringResponse(http.StatusGatewayTimeout, r.Data+" "+r.MatchedPath()+" timed out")
+	})
+	router.Get("/slow", func(ctx context.Context, r *Request[string]) Response {
+		<-ctx.Done()
+		return OK()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusGatewayTimeout, rw.Code)
+	require.Equal(t, "data /slow timed out", rw.Body.String())
+}
+
+func TestTimeout_Panic(t *testing.T) {
+	errSpooky := errors.New("spooky")
+
+	router := New(WithNoData)
+	router.Timeout(time.Second)
+	router.Get("/", func(ctx context.Context, r *Request[NoData]) Response {
+		panic(errSpooky)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rw := httptest.NewRecorder()
+
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		router.ServeHTTP(rw, req)
+	}()
+
+	// The stack of the handler's goroutine is covered by the rescue
+	// middleware's tests.
+	p, ok := recovered.(*Panic)
+	require.True(t, ok, "expected a *Panic, got %T", recovered)
+	require.Equal(t, errSpooky, p.Value)
+	require.ErrorIs(t, p, errSpooky)
+	require.Equal(t, "spooky", p.Error())
+	require.NotEmpty(t, p.PCs)
+}
+
+func TestTimeout_SlowBody(t *testing.T) {
+	readErrs := make(chan error, 1)
+
+	router := New(WithNoData)
+	router.Timeout(50 * time.Millisecond)
+	router.MaxBodySize(1 << 20)
+	router.Post("/upload", func(ctx context.Context, r *Request[NoData]) Response {
+		_, err := io.ReadAll(r.Body())
+		readErrs <- err
+
+		return OK()
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("POST /upload HTTP/1.1\r\nHost: example.com\r\nContent-Length: 100\r\n\r\npartial"))
+	require.NoError(t, err)
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	res, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+
+	select {
+	case err := <-readErrs:
+		require.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler was not stopped from reading the body")
+	}
+}
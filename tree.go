@@ -0,0 +1,315 @@
+package medium
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// routeEntry is a route that has been flattened so it can be dispatched to
+// from the parent group. The handler includes the data creator and
+// BeforeFuncs of every group between the route and the parent.
+type routeEntry[T any] struct {
+	name     string
+	method   string
+	path     string
+	segments []segment
+	// hosts holds the host patterns of the groups the route was defined in,
+	// all of which must match the request's host.
+	hosts []*hostPattern
+	// limits holds the limits of the route and each group it was defined in,
+	// ordered from the outermost group to the route.
+	limits []*limits
+	// middlewares holds the middleware of each group the route was defined
+	// in followed by the route's own middleware.
+	middlewares []Middleware
+	metadata    map[string]any
+	handler     func(context.Context, *Request[T]) Response
+}
+
+// matchHost returns whether the entry's host patterns match host, adding any
+// matched host params to params.
+func (e *routeEntry[T]) matchHost(host string, params map[string]string) bool {
+	for _, pattern := range e.hosts {
+		if !pattern.match(host, params) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// node is a single segment in the route tree. Literal segments are looked up
+// via the static map, while parameters share a single child node since the
+// parameter names are stored on each route entry.
+type node struct {
+	static   map[string]*node
+	patterns []*node
+	param    *node
+	catchAll *node
+	// segment holds the segment that pattern nodes match against.
+	segment segment
+	// entries holds the routes ending at this node by method. Multiple
+	// routes can share a method when they're restricted to different hosts.
+	entries map[string][]*routeEntry[NoData]
+}
+
+// insert adds the entry to the tree, creating nodes as needed. If multiple
+// routes match the same request the first one registered wins.
+func (n *node) insert(entry *routeEntry[NoData]) {
+	current := n
+
+	for _, segment := range entry.segments {
+		current = current.child(segment)
+	}
+
+	if current.entries == nil {
+		current.entries = make(map[string][]*routeEntry[NoData])
+	}
+
+	current.entries[entry.method] = append(current.entries[entry.method], entry)
+}
+
+// entryFor returns the first entry for method that matches host, along with
+// any matched host params. Entries restricted to a host are preferred over
+// entries that match any host.
+func (n *node) entryFor(method string, host string) (*routeEntry[NoData], map[string]string) {
+	entries := n.entries[method]
+
+	for _, entry := range entries {
+		params := make(map[string]string)
+		if len(entry.hosts) > 0 && entry.matchHost(host, params) {
+			return entry, params
+		}
+	}
+
+	for _, entry := range entries {
+		if len(entry.hosts) == 0 {
+			return entry, make(map[string]string)
+		}
+	}
+
+	return nil, nil
+}
+
+// child returns the child node for the given segment, creating it if needed.
+func (n *node) child(segment segment) *node {
+	switch segment.kind {
+	case segmentParam:
+		if n.param == nil {
+			n.param = &node{}
+		}
+
+		return n.param
+	case segmentCatchAll:
+		if n.catchAll == nil {
+			n.catchAll = &node{}
+		}
+
+		return n.catchAll
+	case segmentPattern:
+		for _, child := range n.patterns {
+			if child.segment.value == segment.value {
+				return child
+			}
+		}
+
+		child := &node{segment: segment}
+		n.patterns = append(n.patterns, child)
+
+		return child
+	default:
+		if n.static == nil {
+			n.static = make(map[string]*node)
+		}
+
+		child, ok := n.static[segment.value]
+		if !ok {
+			child = &node{}
+			n.static[segment.value] = child
+		}
+
+		return child
+	}
+}
+
+// walk calls visit for every node matching path. Literal segments are
+// preferred, followed by patterns in the order they were registered, then
+// parameters, then catch-alls. Parameter values are collected in order and
+// passed to visit. Walking stops as soon as visit returns true.
+func (n *node) walk(path string, values []string, visit func(*node, []string) bool) bool {
+	part, rest, last := nextSegment(path)
+
+	if child, ok := n.static[part]; ok && child.follow(rest, last, values, visit) {
+		return true
+	}
+
+	for _, child := range n.patterns {
+		if values, ok := child.segment.match(part, values); ok && child.follow(rest, last, values, visit) {
+			return true
+		}
+	}
+
+	if n.param != nil && n.param.follow(rest, last, append(values, part), visit) {
+		return true
+	}
+
+	if n.catchAll != nil && n.catchAll.entries != nil {
+		return visit(n.catchAll, append(values, path))
+	}
+
+	return false
+}
+
+// follow continues walking from n, which matched the current segment. If the
+// segment was the last in the path n is visited, otherwise the rest of the
+// path is walked.
+func (n *node) follow(rest string, last bool, values []string, visit func(*node, []string) bool) bool {
+	if last {
+		return n.entries != nil && visit(n, values)
+	}
+
+	return n.walk(rest, values, visit)
+}
+
+// routeTable holds the compiled route tree of a Router. The tree is rebuilt
+// lazily on the next lookup after a route is added to the router or any of
+// its groups.
+type routeTable struct {
+	mu      sync.RWMutex
+	root    *node
+	stale   bool
+	compile func() []*routeEntry[NoData]
+	// named holds the segments of named routes, used to generate URLs.
+	named map[string][][]segment
+}
+
+func newRouteTable(compile func() []*routeEntry[NoData]) *routeTable {
+	return &routeTable{compile: compile, stale: true, named: make(map[string][][]segment)}
+}
+
+// name registers the segments of a named route. It panics if the name has
+// already been used by another route.
+func (t *routeTable) name(name string, variants [][]segment) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.named[name]; ok {
+		panic(fmt.Sprintf("route named %s is already defined", name))
+	}
+
+	t.named[name] = variants
+}
+
+// invalidate marks the tree as needing to be rebuilt.
+func (t *routeTable) invalidate() {
+	t.mu.Lock()
+	t.stale = true
+	t.mu.Unlock()
+}
+
+// tree returns the compiled route tree, building it if routes have changed.
+func (t *routeTable) tree() *node {
+	t.mu.RLock()
+	if !t.stale {
+		defer t.mu.RUnlock()
+		return t.root
+	}
+	t.mu.RUnlock()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stale {
+		root := &node{}
+		for _, entry := range t.compile() {
+			root.insert(entry)
+		}
+
+		t.root = root
+		t.stale = false
+	}
+
+	return t.root
+}
+
+// lookup returns the route entry that matches the given method, host and path
+// along with the matched route data. HEAD requests fall back to GET routes
+// when no HEAD route is defined, and all requests fall back to routes that
+// respond to any method. If no route matches, nil is returned.
+func (t *routeTable) lookup(method string, host string, path string) (*routeEntry[NoData], *RouteData) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, nil
+	}
+
+	var match *routeEntry[NoData]
+	var routeData *RouteData
+
+	t.tree().walk(path[1:], make([]string, 0, 8), func(n *node, values []string) bool {
+		entry, params := n.entryFor(method, host)
+		if entry == nil && method == http.MethodHead {
+			entry, params = n.entryFor(http.MethodGet, host)
+		}
+
+		if entry == nil {
+			entry, params = n.entryFor(anyMethod, host)
+		}
+
+		if entry == nil {
+			return false
+		}
+
+		for name, value := range paramsFor(entry.segments, values) {
+			params[name] = value
+		}
+
+		match = entry
+		routeData = &RouteData{Params: params, HandlerPath: entry.path, Metadata: entry.metadata}
+
+		return true
+	})
+
+	return match, routeData
+}
+
+// allowedMethods returns the sorted methods of every route matching host and
+// path, regardless of the method the routes were registered with. HEAD and
+// OPTIONS are included since they are answered automatically by the router.
+func (t *routeTable) allowedMethods(host string, path string) []string {
+	if !strings.HasPrefix(path, "/") {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	methods := make([]string, 0)
+
+	t.tree().walk(path[1:], make([]string, 0, 8), func(n *node, values []string) bool {
+		for method := range n.entries {
+			if entry, _ := n.entryFor(method, host); entry != nil && !seen[method] {
+				seen[method] = true
+				methods = append(methods, method)
+			}
+		}
+
+		return false
+	})
+
+	if len(methods) == 0 {
+		return methods
+	}
+
+	if seen[http.MethodGet] && !seen[http.MethodHead] {
+		methods = append(methods, http.MethodHead)
+	}
+
+	if !seen[http.MethodOptions] {
+		methods = append(methods, http.MethodOptions)
+	}
+
+	sort.Strings(methods)
+
+	return methods
+}
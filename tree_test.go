@@ -0,0 +1,165 @@
+package medium
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree_Lookup(t *testing.T) {
+	router := New(WithNoData)
+
+	routes := []string{
+		"/",
+		"/users",
+		"/users/new",
+		"/users/:id",
+		"/users/:id/edit",
+		"/users/:userID/posts/:postID",
+		"/teams/:id/members",
+		"/teams/new/settings",
+	}
+
+	for _, route := range routes {
+		route := route
+		router.Get(route, func(ctx context.Context, r *Request[NoData]) Response {
+			return StringResponse(http.StatusOK, route)
+		})
+	}
+
+	testCases := map[string]struct {
+		path    string
+		pattern string
+		params  map[string]string
+	}{
+		"root":                {path: "/", pattern: "/", params: map[string]string{}},
+		"static":              {path: "/users", pattern: "/users", params: map[string]string{}},
+		"static over param":   {path: "/users/new", pattern: "/users/new", params: map[string]string{}},
+		"param":               {path: "/users/1", pattern: "/users/:id", params: map[string]string{"id": "1"}},
+		"nested param":        {path: "/users/1/edit", pattern: "/users/:id/edit", params: map[string]string{"id": "1"}},
+		"differently named":   {path: "/users/1/posts/2", pattern: "/users/:userID/posts/:postID", params: map[string]string{"userID": "1", "postID": "2"}},
+		"backtracks to param": {path: "/teams/new/members", pattern: "/teams/:id/members", params: map[string]string{"id": "new"}},
+		"trailing slash":      {path: "/users/", pattern: "/users/:id", params: map[string]string{"id": ""}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			entry, routeData := router.routeGroup.tree.lookup(http.MethodGet, "example.com", tc.path)
+
+			require.NotNil(t, entry)
+			require.Equal(t, tc.pattern, routeData.HandlerPath)
+			require.Equal(t, tc.params, routeData.Params)
+		})
+	}
+
+	entry, _ := router.routeGroup.tree.lookup(http.MethodGet, "example.com", "/users/1/edit/more")
+	require.Nil(t, entry)
+
+	entry, _ = router.routeGroup.tree.lookup(http.MethodPost, "example.com", "/users")
+	require.Nil(t, entry)
+}
+
+func TestTree_Lookup_Wildcards(t *testing.T) {
+	router := New(WithNoData)
+
+	routes := []string{
+		"/files/:name.:ext",
+		"/files/:name",
+		"/files/special.txt",
+		"/posts/:page?",
+		"/app/*path",
+		"/app/settings",
+	}
+
+	for _, route := range routes {
+		route := route
+		router.Get(route, func(ctx context.Context, r *Request[NoData]) Response {
+			return StringResponse(http.StatusOK, route)
+		})
+	}
+
+	testCases := map[string]struct {
+		path    string
+		pattern string
+		params  map[string]string
+	}{
+		"static over pattern":   {path: "/files/special.txt", pattern: "/files/special.txt", params: map[string]string{}},
+		"pattern over param":    {path: "/files/notes.md", pattern: "/files/:name.:ext", params: map[string]string{"name": "notes", "ext": "md"}},
+		"param when no pattern": {path: "/files/notes", pattern: "/files/:name", params: map[string]string{"name": "notes"}},
+		"optional present":      {path: "/posts/3", pattern: "/posts/:page?", params: map[string]string{"page": "3"}},
+		"optional omitted":      {path: "/posts", pattern: "/posts/:page?", params: map[string]string{}},
+		"static over catch-all": {path: "/app/settings", pattern: "/app/settings", params: map[string]string{}},
+		"catch-all":             {path: "/app/settings/profile", pattern: "/app/*path", params: map[string]string{"path": "settings/profile"}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			entry, routeData := router.routeGroup.tree.lookup(http.MethodGet, "example.com", tc.path)
+
+			require.NotNil(t, entry)
+			require.Equal(t, tc.pattern, routeData.HandlerPath)
+			require.Equal(t, tc.params, routeData.Params)
+		})
+	}
+}
+
+func TestTree_FirstRegisteredWins(t *testing.T) {
+	router := New(WithNoData)
+
+	router.Get("/hello", func(ctx context.Context, r *Request[NoData]) Response {
+		return StringResponse(http.StatusOK, "router")
+	})
+
+	group := Group(router, WithNoData)
+	group.Get("/hello", func(ctx context.Context, r *Request[NoData]) Response {
+		return StringResponse(http.StatusOK, "group")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, "router", rw.Body.String())
+}
+
+func TestTree_RoutesAddedAfterServing(t *testing.T) {
+	router := New(WithNoData)
+	group := SubRouter(router, "/late", WithNoData)
+
+	req := httptest.NewRequest(http.MethodGet, "/late/route", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusNotFound, rw.Code)
+
+	group.Get("/route", func(ctx context.Context, r *Request[NoData]) Response {
+		return OK()
+	})
+
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+}
+
+func BenchmarkRouter_ManyRoutes(b *testing.B) {
+	router := New(WithNoData)
+
+	for i := 0; i < 500; i++ {
+		group := SubRouter(router, fmt.Sprintf("/resource%d", i), WithNoData)
+		group.Get("/:id", func(ctx context.Context, r *Request[NoData]) Response { return OK() })
+		group.Post("/:id/edit", func(ctx context.Context, r *Request[NoData]) Response { return OK() })
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/resource499/1/edit", nil)
+	rw := httptest.NewRecorder()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		router.ServeHTTP(rw, req)
+	}
+}
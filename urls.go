@@ -0,0 +1,117 @@
+package medium
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrUnknownRoute is returned by URLFor when no route has the given name.
+	ErrUnknownRoute = errors.New("no route with the given name")
+	// ErrInvalidParam is returned by URLFor when a param does not satisfy the
+	// route's constraints.
+	ErrInvalidParam = errors.New("route param is invalid")
+)
+
+// URLFor returns the path for the route registered with the given name via
+// WithName, including the prefixes of any groups or subrouters the route was
+// defined on. Params are used to fill in the route's params and query, if
+// present, is appended as the query string.
+//
+// An error is returned if no route has the given name, a required param is
+// missing, or a param does not satisfy the route's constraints.
+func (r *Router[T]) URLFor(name string, params map[string]string, query url.Values) (string, error) {
+	return r.routeGroup.tree.urlFor(name, params, query)
+}
+
+// urlFor builds the URL for the named route. Routes with optional params try
+// each variant in turn so that omitted params are left out of the URL.
+func (t *routeTable) urlFor(name string, params map[string]string, query url.Values) (string, error) {
+	t.mu.RLock()
+	variants, ok := t.named[name]
+	t.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownRoute, name)
+	}
+
+	var err error
+	for _, segments := range variants {
+		var path string
+		path, err = buildPath(segments, params)
+
+		if errors.Is(err, ErrMissingParam) {
+			continue
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+
+		return path, nil
+	}
+
+	return "", err
+}
+
+// buildPath builds a path from the given segments, escaping param values.
+func buildPath(segments []segment, params map[string]string) (string, error) {
+	parts := make([]string, len(segments))
+
+	for i, segment := range segments {
+		switch segment.kind {
+		case segmentStatic:
+			parts[i] = segment.value
+		case segmentParam:
+			value := params[segment.value]
+			if value == "" {
+				return "", fmt.Errorf("%w: %s", ErrMissingParam, segment.value)
+			}
+
+			parts[i] = url.PathEscape(value)
+		case segmentCatchAll:
+			value, ok := params[segment.value]
+			if !ok {
+				return "", fmt.Errorf("%w: %s", ErrMissingParam, segment.value)
+			}
+
+			pieces := strings.Split(value, "/")
+			for j, piece := range pieces {
+				pieces[j] = url.PathEscape(piece)
+			}
+
+			parts[i] = strings.Join(pieces, "/")
+		case segmentPattern:
+			var raw, escaped strings.Builder
+
+			for j, name := range segment.names {
+				value := params[name]
+				if value == "" {
+					return "", fmt.Errorf("%w: %s", ErrMissingParam, name)
+				}
+
+				raw.WriteString(segment.literals[j])
+				raw.WriteString(value)
+				escaped.WriteString(segment.literals[j])
+				escaped.WriteString(url.PathEscape(value))
+			}
+
+			raw.WriteString(segment.literals[len(segment.names)])
+			escaped.WriteString(segment.literals[len(segment.names)])
+
+			if !segment.pattern.MatchString(raw.String()) {
+				return "", fmt.Errorf("%w: %s does not match %s", ErrInvalidParam, raw.String(), segment.value)
+			}
+
+			parts[i] = escaped.String()
+		}
+	}
+
+	return "/" + strings.Join(parts, "/"), nil
+}
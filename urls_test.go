@@ -0,0 +1,88 @@
+package medium
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_URLFor(t *testing.T) {
+	handler := func(ctx context.Context, r *Request[NoData]) Response { return OK() }
+
+	router := New(WithNoData)
+	router.Get("/", handler, WithName("root"))
+	router.Get("/posts/:page<int>?", handler, WithName("posts"))
+	router.Get("/files/:name.:ext", handler, WithName("file"))
+	router.Get("/assets/*path", handler, WithName("asset"))
+
+	teams := SubRouter(router, "/teams/:teamID", WithNoData)
+	teams.Get("/", handler, WithName("team"))
+
+	settings := SubRouter(teams, "/settings", WithNoData)
+	settings.Get("/members/:memberID", handler, WithName("member_settings"))
+
+	testCases := map[string]struct {
+		route  string
+		params map[string]string
+		query  url.Values
+		want   string
+	}{
+		"root":             {route: "root", want: "/"},
+		"optional present": {route: "posts", params: map[string]string{"page": "2"}, want: "/posts/2"},
+		"optional omitted": {route: "posts", want: "/posts"},
+		"embedded":         {route: "file", params: map[string]string{"name": "notes", "ext": "md"}, want: "/files/notes.md"},
+		"catch-all":        {route: "asset", params: map[string]string{"path": "js/my app.js"}, want: "/assets/js/my%20app.js"},
+		"subrouter":        {route: "team", params: map[string]string{"teamID": "1"}, want: "/teams/1"},
+		"nested subrouter": {route: "member_settings", params: map[string]string{"teamID": "1", "memberID": "2"}, want: "/teams/1/settings/members/2"},
+		"escapes params":   {route: "team", params: map[string]string{"teamID": "a/b"}, want: "/teams/a%2Fb"},
+		"query": {
+			route:  "team",
+			params: map[string]string{"teamID": "1"},
+			query:  url.Values{"tab": {"members"}, "q": {"fox mulder"}},
+			want:   "/teams/1?q=fox+mulder&tab=members",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := router.URLFor(tc.route, tc.params, tc.query)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRouter_URLFor_Errors(t *testing.T) {
+	handler := func(ctx context.Context, r *Request[NoData]) Response { return OK() }
+
+	router := New(WithNoData)
+	router.Get("/users/:id<int>", handler, WithName("user"))
+
+	_, err := router.URLFor("missing", nil, nil)
+	require.ErrorIs(t, err, ErrUnknownRoute)
+
+	_, err = router.URLFor("user", nil, nil)
+	require.ErrorIs(t, err, ErrMissingParam)
+
+	_, err = router.URLFor("user", map[string]string{"id": "fox"}, nil)
+	require.ErrorIs(t, err, ErrInvalidParam)
+}
+
+func TestRouter_DuplicateName(t *testing.T) {
+	handler := func(ctx context.Context, r *Request[NoData]) Response { return OK() }
+
+	router := New(WithNoData)
+	router.Get("/users", handler, WithName("users"))
+
+	group := Group(router, WithNoData)
+
+	require.Panics(t, func() {
+		group.Post("/users", handler, WithName("users"))
+	})
+
+	_, err := router.URLFor("users", nil, nil)
+	require.NoError(t, err)
+}
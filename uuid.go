@@ -0,0 +1,47 @@
+package medium
+
+import (
+	"encoding/hex"
+	"errors"
+)
+
+// ErrInvalidUUID is returned when a string can't be parsed as a UUID.
+var ErrInvalidUUID = errors.New("invalid uuid")
+
+// UUID represents a parsed UUID, such as one matched by the `uuid` route param
+// constraint.
+type UUID [16]byte
+
+// ParseUUID parses the canonical, hyphenated, form of a UUID, e.g.
+// `f47ac10b-58cc-4372-a567-0e02b2c3d479`.
+func ParseUUID(value string) (UUID, error) {
+	var uuid UUID
+
+	if len(value) != 36 || value[8] != '-' || value[13] != '-' || value[18] != '-' || value[23] != '-' {
+		return uuid, ErrInvalidUUID
+	}
+
+	hexValue := value[0:8] + value[9:13] + value[14:18] + value[19:23] + value[24:36]
+	if _, err := hex.Decode(uuid[:], []byte(hexValue)); err != nil {
+		return UUID{}, ErrInvalidUUID
+	}
+
+	return uuid, nil
+}
+
+// String returns the canonical, hyphenated, form of the UUID.
+func (u UUID) String() string {
+	buf := make([]byte, 36)
+
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+
+	return string(buf)
+}
@@ -0,0 +1,20 @@
+package medium
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUUID(t *testing.T) {
+	uuid, err := ParseUUID("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+
+	require.NoError(t, err)
+	require.Equal(t, "f47ac10b-58cc-4372-a567-0e02b2c3d479", uuid.String())
+
+	_, err = ParseUUID("f47ac10b58cc4372a5670e02b2c3d479")
+	require.ErrorIs(t, err, ErrInvalidUUID)
+
+	_, err = ParseUUID("z47ac10b-58cc-4372-a567-0e02b2c3d479")
+	require.ErrorIs(t, err, ErrInvalidUUID)
+}
@@ -0,0 +1,601 @@
+package medium
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/blakewilliams/medium/mlog"
+)
+
+// websocketGUID is appended to the client's key to compute the
+// Sec-WebSocket-Accept header, as defined by RFC 6455.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// DefaultMaxMessageSize is the largest message a WebSocketConn will read
+// unless configured otherwise.
+const DefaultMaxMessageSize = 1 << 20
+
+// MessageType is the type of a WebSocket message.
+type MessageType int
+
+const (
+	// TextMessage is a message containing UTF-8 encoded text.
+	TextMessage MessageType = 1
+	// BinaryMessage is a message containing arbitrary bytes.
+	BinaryMessage MessageType = 2
+)
+
+// frame opcodes, as defined by RFC 6455.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// CloseCode is the status code sent when closing a WebSocket connection.
+type CloseCode int
+
+// Close codes defined by RFC 6455.
+const (
+	CloseNormal             CloseCode = 1000
+	CloseGoingAway          CloseCode = 1001
+	CloseProtocolError      CloseCode = 1002
+	CloseUnsupportedData    CloseCode = 1003
+	CloseNoStatus           CloseCode = 1005
+	CloseAbnormal           CloseCode = 1006
+	CloseInvalidPayload     CloseCode = 1007
+	ClosePolicyViolation    CloseCode = 1008
+	CloseMessageTooBig      CloseCode = 1009
+	CloseMandatoryExtension CloseCode = 1010
+	CloseInternalError      CloseCode = 1011
+)
+
+// CloseError is returned by WebSocketConn.ReadMessage when the connection has
+// been closed, either by the client or due to a protocol error.
+type CloseError struct {
+	Code   CloseCode
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("websocket closed: %d", e.Code)
+	}
+
+	return fmt.Sprintf("websocket closed: %d %s", e.Code, e.Reason)
+}
+
+// WebSocketConfig configures a WebSocket connection created by
+// WebSocketWithConfig.
+type WebSocketConfig struct {
+	// MaxMessageSize is the largest message, in bytes, that will be read from
+	// the client. Larger messages close the connection with
+	// CloseMessageTooBig. Defaults to DefaultMaxMessageSize.
+	MaxMessageSize int64
+	// Subprotocols holds the supported subprotocols in order of preference.
+	// The first subprotocol requested by the client that is supported is
+	// selected and available via WebSocketConn.Subprotocol.
+	Subprotocols []string
+	// CheckOrigin returns whether the request's Origin is allowed to connect.
+	// Defaults to allowing requests without an Origin header or with an
+	// Origin matching the request's host.
+	CheckOrigin func(r *http.Request) bool
+	// PingInterval is how often a ping is sent to the client while the
+	// connection is open. Pings are not sent if zero.
+	PingInterval time.Duration
+}
+
+// WebSocketFunc handles a WebSocket connection. The connection is closed once
+// it returns, with CloseNormal if it returns nil and CloseInternalError
+// otherwise.
+type WebSocketFunc func(ctx context.Context, conn *WebSocketConn) error
+
+// WebSocketResponse is a Response that upgrades the connection to the
+// WebSocket protocol. See WebSocket for more information.
+type WebSocketResponse struct {
+	ctx    context.Context
+	header http.Header
+	config WebSocketConfig
+	fn     WebSocketFunc
+}
+
+var _ writerResponse = (*WebSocketResponse)(nil)
+
+// WebSocket returns a response that upgrades the connection to the WebSocket
+// protocol and calls fn with the connection once the handshake completes.
+// Requests that aren't valid WebSocket handshakes receive a 400 Bad Request.
+//
+// Since WebSocket is returned from a regular handler, BeforeFuncs such as
+// authentication apply to WebSocket routes the same way they apply to other
+// routes. ctx should be the context passed to the handler so that values added
+// by BeforeFuncs are available to fn.
+func WebSocket(ctx context.Context, fn WebSocketFunc) *WebSocketResponse {
+	return WebSocketWithConfig(ctx, WebSocketConfig{}, fn)
+}
+
+// WebSocketWithConfig has the same behavior as WebSocket but accepts a
+// WebSocketConfig.
+func WebSocketWithConfig(ctx context.Context, config WebSocketConfig, fn WebSocketFunc) *WebSocketResponse {
+	if config.MaxMessageSize == 0 {
+		config.MaxMessageSize = DefaultMaxMessageSize
+	}
+
+	if config.CheckOrigin == nil {
+		config.CheckOrigin = sameOrigin
+	}
+
+	return &WebSocketResponse{ctx: ctx, header: http.Header{}, config: config, fn: fn}
+}
+
+// Status returns 101 Switching Protocols.
+func (wr *WebSocketResponse) Status() int { return http.StatusSwitchingProtocols }
+
+// Header returns the header map sent with the handshake response.
+func (wr *WebSocketResponse) Header() http.Header { return wr.header }
+
+// Body returns nil since WebSocket responses take over the connection.
+func (wr *WebSocketResponse) Body() io.Reader { return nil }
+
+func (wr *WebSocketResponse) writeResponse(rw http.ResponseWriter, r *http.Request) {
+	key, status := checkHandshake(r)
+	if status == http.StatusUpgradeRequired {
+		rw.Header().Set("Sec-WebSocket-Version", "13")
+	}
+
+	if status == 0 && !wr.config.CheckOrigin(r) {
+		status = http.StatusForbidden
+	}
+
+	if status != 0 {
+		http.Error(rw, fmt.Sprintf("%d %s", status, strings.ToLower(http.StatusText(status))), status)
+		return
+	}
+
+	hijacker, ok := unwrapWriter[http.Hijacker](rw)
+	if !ok {
+		http.Error(rw, "500 internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	netConn, brw, err := hijacker.Hijack()
+	if err != nil {
+		mlog.Error(wr.ctx, "error hijacking websocket connection", mlog.Fields{"error": err})
+		return
+	}
+	defer netConn.Close()
+
+	header := rw.Header().Clone()
+	header.Del("Content-Type")
+	header.Del("Content-Length")
+	header.Set("Upgrade", "websocket")
+	header.Set("Connection", "Upgrade")
+	header.Set("Sec-WebSocket-Accept", acceptKey(key))
+
+	subprotocol := selectSubprotocol(r, wr.config.Subprotocols)
+	if subprotocol != "" {
+		header.Set("Sec-WebSocket-Protocol", subprotocol)
+	}
+
+	brw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	_ = header.Write(brw)
+	brw.WriteString("\r\n")
+	if err := brw.Flush(); err != nil {
+		return
+	}
+
+	// Hijacked connections have no deadlines, clear any set by the server.
+	_ = netConn.SetDeadline(time.Time{})
+
+	ctx, cancel := context.WithCancel(wr.ctx)
+	defer cancel()
+
+	conn := &WebSocketConn{
+		conn:           netConn,
+		reader:         brw.Reader,
+		maxMessageSize: wr.config.MaxMessageSize,
+		subprotocol:    subprotocol,
+	}
+
+	if wr.config.PingInterval > 0 {
+		go conn.keepAlive(ctx, wr.config.PingInterval)
+	}
+
+	err = wr.fn(ctx, conn)
+
+	var closeErr *CloseError
+	switch {
+	case err == nil:
+		_ = conn.Close(CloseNormal, "")
+	case errors.As(err, &closeErr):
+		_ = conn.Close(CloseNormal, "")
+	default:
+		mlog.Error(ctx, "error handling websocket connection", mlog.Fields{"error": err})
+		_ = conn.Close(CloseInternalError, "")
+	}
+}
+
+// checkHandshake validates the WebSocket handshake, returning the client's key
+// if it's valid and an error status otherwise.
+func checkHandshake(r *http.Request) (string, int) {
+	if r.Method != http.MethodGet {
+		return "", http.StatusBadRequest
+	}
+
+	if !headerContainsToken(r.Header, "Connection", "upgrade") || !headerContainsToken(r.Header, "Upgrade", "websocket") {
+		return "", http.StatusBadRequest
+	}
+
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return "", http.StatusUpgradeRequired
+	}
+
+	key := strings.TrimSpace(r.Header.Get("Sec-WebSocket-Key"))
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return "", http.StatusBadRequest
+	}
+
+	return key, 0
+}
+
+// acceptKey returns the Sec-WebSocket-Accept value for the client's key.
+func acceptKey(key string) string {
+	hash := sha1.Sum([]byte(key + websocketGUID))
+
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// headerContainsToken reports whether the comma separated header contains
+// token, ignoring case.
+func headerContainsToken(header http.Header, name string, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// selectSubprotocol returns the first supported subprotocol requested by the
+// client, or an empty string if none match.
+func selectSubprotocol(r *http.Request, supported []string) string {
+	for _, value := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, requested := range strings.Split(value, ",") {
+			requested = strings.TrimSpace(requested)
+
+			for _, protocol := range supported {
+				if protocol == requested {
+					return protocol
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// sameOrigin allows requests without an Origin header or with an Origin whose
+// host matches the request's host.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	_, host, ok := strings.Cut(origin, "://")
+
+	return ok && strings.EqualFold(host, r.Host)
+}
+
+// WebSocketConn is an open WebSocket connection. Messages can be written
+// concurrently with reads, but only one goroutine may read at a time.
+type WebSocketConn struct {
+	conn           net.Conn
+	reader         *bufio.Reader
+	maxMessageSize int64
+	subprotocol    string
+
+	writeMu sync.Mutex
+	closed  bool
+}
+
+// Subprotocol returns the subprotocol selected during the handshake, if any.
+func (c *WebSocketConn) Subprotocol() string { return c.subprotocol }
+
+// ReadMessage reads the next text or binary message, reassembling fragmented
+// messages. Pings are answered automatically and pongs are ignored.
+//
+// If the client closes the connection or violates the protocol, a *CloseError
+// is returned and the connection is closed.
+func (c *WebSocketConn) ReadMessage() (MessageType, []byte, error) {
+	var messageType MessageType
+	var message []byte
+	fragmented := false
+
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+
+			continue
+		case opPong:
+			continue
+		case opClose:
+			return 0, nil, c.handleClose(payload)
+		case opContinuation:
+			if !fragmented {
+				return 0, nil, c.fail(CloseProtocolError, "unexpected continuation frame")
+			}
+		case opText, opBinary:
+			if fragmented {
+				return 0, nil, c.fail(CloseProtocolError, "expected continuation frame")
+			}
+
+			messageType = MessageType(opcode)
+			fragmented = true
+		default:
+			return 0, nil, c.fail(CloseProtocolError, "unknown opcode")
+		}
+
+		if int64(len(message))+int64(len(payload)) > c.maxMessageSize {
+			return 0, nil, c.fail(CloseMessageTooBig, "message too big")
+		}
+
+		message = append(message, payload...)
+
+		if fin {
+			if messageType == TextMessage && !utf8.Valid(message) {
+				return 0, nil, c.fail(CloseInvalidPayload, "invalid utf-8")
+			}
+
+			return messageType, message, nil
+		}
+	}
+}
+
+// readFrame reads a single frame from the client, unmasking its payload.
+func (c *WebSocketConn) readFrame() (bool, byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, c.abnormal(err)
+	}
+
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, c.fail(CloseProtocolError, "reserved bits set")
+	}
+
+	if header[1]&0x80 == 0 {
+		return false, 0, nil, c.fail(CloseProtocolError, "client frames must be masked")
+	}
+
+	length := int64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, c.abnormal(err)
+		}
+
+		length = int64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, c.abnormal(err)
+		}
+
+		length = int64(binary.BigEndian.Uint64(extended[:]))
+		if length < 0 {
+			return false, 0, nil, c.fail(CloseProtocolError, "invalid frame length")
+		}
+	}
+
+	if opcode >= opClose && (!fin || length > 125) {
+		return false, 0, nil, c.fail(CloseProtocolError, "invalid control frame")
+	}
+
+	if length > c.maxMessageSize {
+		return false, 0, nil, c.fail(CloseMessageTooBig, "message too big")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return false, 0, nil, c.abnormal(err)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, c.abnormal(err)
+	}
+
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// handleClose responds to a close frame sent by the client, returning the
+// resulting CloseError.
+func (c *WebSocketConn) handleClose(payload []byte) error {
+	closeErr := &CloseError{Code: CloseNoStatus}
+
+	switch {
+	case len(payload) == 1:
+		return c.fail(CloseProtocolError, "invalid close frame")
+	case len(payload) >= 2:
+		closeErr.Code = CloseCode(binary.BigEndian.Uint16(payload))
+		closeErr.Reason = string(payload[2:])
+
+		if !validCloseCode(closeErr.Code) {
+			return c.fail(CloseProtocolError, "invalid close code")
+		}
+
+		if !utf8.ValidString(closeErr.Reason) {
+			return c.fail(CloseInvalidPayload, "invalid utf-8")
+		}
+	}
+
+	code := closeErr.Code
+	if code == CloseNoStatus {
+		code = CloseNormal
+	}
+	_ = c.Close(code, "")
+
+	return closeErr
+}
+
+// fail closes the connection due to a protocol error, returning the
+// resulting CloseError.
+func (c *WebSocketConn) fail(code CloseCode, reason string) error {
+	_ = c.Close(code, reason)
+
+	return &CloseError{Code: code, Reason: reason}
+}
+
+// abnormal returns a CloseError for connections closed without a close frame.
+func (c *WebSocketConn) abnormal(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, net.ErrClosed) {
+		return &CloseError{Code: CloseAbnormal}
+	}
+
+	return err
+}
+
+// WriteMessage sends a text or binary message to the client.
+func (c *WebSocketConn) WriteMessage(messageType MessageType, data []byte) error {
+	if messageType != TextMessage && messageType != BinaryMessage {
+		return fmt.Errorf("invalid websocket message type %d", messageType)
+	}
+
+	return c.writeFrame(byte(messageType), data)
+}
+
+// WriteText sends a text message to the client.
+func (c *WebSocketConn) WriteText(text string) error {
+	return c.WriteMessage(TextMessage, []byte(text))
+}
+
+// Ping sends a ping to the client, which should respond with a pong.
+func (c *WebSocketConn) Ping(data []byte) error {
+	if len(data) > 125 {
+		return errors.New("websocket ping payload must be 125 bytes or less")
+	}
+
+	return c.writeFrame(opPing, data)
+}
+
+// Close sends a close frame with the given code and reason to the client.
+// Subsequent writes return an error. The underlying connection is closed once
+// the WebSocketFunc returns.
+func (c *WebSocketConn) Close(code CloseCode, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+
+	if len(payload) > 125 {
+		payload = payload[:125]
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	return c.writeFrameLocked(opClose, payload)
+}
+
+// writeFrame writes a single unmasked, unfragmented frame to the client.
+func (c *WebSocketConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed {
+		return &CloseError{Code: CloseNormal, Reason: "connection closed"}
+	}
+
+	return c.writeFrameLocked(opcode, payload)
+}
+
+func (c *WebSocketConn) writeFrameLocked(opcode byte, payload []byte) error {
+	frame := make([]byte, 10, 10+len(payload))
+	frame[0] = 0x80 | opcode
+
+	switch length := len(payload); {
+	case length <= 125:
+		frame[1] = byte(length)
+		frame = frame[:2]
+	case length <= 0xFFFF:
+		frame[1] = 126
+		binary.BigEndian.PutUint16(frame[2:], uint16(length))
+		frame = frame[:4]
+	default:
+		frame[1] = 127
+		binary.BigEndian.PutUint64(frame[2:], uint64(length))
+	}
+
+	if _, err := c.conn.Write(append(frame, payload...)); err != nil {
+		return c.abnormal(err)
+	}
+
+	return nil
+}
+
+// keepAlive sends a ping every interval until ctx is canceled or writing
+// fails.
+func (c *WebSocketConn) keepAlive(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Ping(nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// validCloseCode reports whether code may be sent in a close frame.
+func validCloseCode(code CloseCode) bool {
+	switch code {
+	case CloseNormal, CloseGoingAway, CloseProtocolError, CloseUnsupportedData,
+		CloseInvalidPayload, ClosePolicyViolation, CloseMessageTooBig, CloseMandatoryExtension, CloseInternalError:
+		return true
+	}
+
+	// Codes 3000-4999 are reserved for libraries and applications.
+	return code >= 3000 && code <= 4999
+}
@@ -0,0 +1,333 @@
+package medium
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testSocket is a minimal WebSocket client used to test the server.
+type testSocket struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dialTestSocket(t *testing.T, server *httptest.Server, path string, header http.Header) (*testSocket, *http.Response) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	require.NoError(t, err)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	require.NoError(t, req.Write(conn))
+
+	reader := bufio.NewReader(conn)
+	res, err := http.ReadResponse(reader, req)
+	require.NoError(t, err)
+
+	return &testSocket{t: t, conn: conn, reader: reader}, res
+}
+
+func (s *testSocket) writeFrame(fin bool, opcode byte, payload []byte) {
+	s.t.Helper()
+
+	frame := []byte{opcode, 0x80}
+	if fin {
+		frame[0] |= 0x80
+	}
+
+	switch {
+	case len(payload) <= 125:
+		frame[1] |= byte(len(payload))
+	case len(payload) <= 0xFFFF:
+		frame[1] |= 126
+		frame = append(frame, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(len(payload)))
+	default:
+		frame[1] |= 127
+		frame = append(frame, make([]byte, 8)...)
+		binary.BigEndian.PutUint64(frame[2:], uint64(len(payload)))
+	}
+
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	_, err := s.conn.Write(frame)
+	require.NoError(s.t, err)
+}
+
+func (s *testSocket) readFrame() (byte, []byte) {
+	s.t.Helper()
+
+	var header [2]byte
+	_, err := io.ReadFull(s.reader, header[:])
+	require.NoError(s.t, err)
+	require.Zero(s.t, header[1]&0x80, "server frames must not be masked")
+
+	length := int(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		_, err := io.ReadFull(s.reader, extended[:])
+		require.NoError(s.t, err)
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		_, err := io.ReadFull(s.reader, extended[:])
+		require.NoError(s.t, err)
+		length = int(binary.BigEndian.Uint64(extended[:]))
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(s.reader, payload)
+	require.NoError(s.t, err)
+
+	return header[0] & 0x0F, payload
+}
+
+func (s *testSocket) expectClose(code CloseCode) {
+	s.t.Helper()
+
+	opcode, payload := s.readFrame()
+	require.Equal(s.t, byte(opClose), opcode)
+	require.GreaterOrEqual(s.t, len(payload), 2)
+	require.Equal(s.t, code, CloseCode(binary.BigEndian.Uint16(payload)))
+}
+
+func closePayload(code CloseCode, reason string) []byte {
+	payload := make([]byte, 2)
+	binary.BigEndian.PutUint16(payload, uint16(code))
+
+	return append(payload, reason...)
+}
+
+func echoRouter(config WebSocketConfig, closed chan<- error) *Router[NoData] {
+	router := New(WithNoData)
+	router.Get("/echo", func(ctx context.Context, r *Request[NoData]) Response {
+		return WebSocketWithConfig(ctx, config, func(ctx context.Context, conn *WebSocketConn) error {
+			for {
+				messageType, message, err := conn.ReadMessage()
+				if err != nil {
+					if closed != nil {
+						closed <- err
+					}
+
+					return err
+				}
+
+				if err := conn.WriteMessage(messageType, message); err != nil {
+					return err
+				}
+			}
+		})
+	})
+
+	return router
+}
+
+func TestWebSocket_Handshake(t *testing.T) {
+	server := httptest.NewServer(echoRouter(WebSocketConfig{Subprotocols: []string{"chat"}}, nil))
+	defer server.Close()
+
+	_, res := dialTestSocket(t, server, "/echo", http.Header{"Sec-Websocket-Protocol": {"superchat, chat"}})
+
+	require.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+	require.Equal(t, "websocket", res.Header.Get("Upgrade"))
+	require.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", res.Header.Get("Sec-WebSocket-Accept"))
+	require.Equal(t, "chat", res.Header.Get("Sec-WebSocket-Protocol"))
+}
+
+func TestWebSocket_InvalidHandshake(t *testing.T) {
+	router := echoRouter(WebSocketConfig{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/echo", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+	require.Equal(t, http.StatusBadRequest, rw.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/echo", nil)
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "8")
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+	require.Equal(t, http.StatusUpgradeRequired, rw.Code)
+	require.Equal(t, "13", rw.Header().Get("Sec-WebSocket-Version"))
+
+	req = httptest.NewRequest(http.MethodGet, "/echo", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Origin", "https://evil.example")
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+	require.Equal(t, http.StatusForbidden, rw.Code)
+}
+
+func TestWebSocket_EchoAndFragmentation(t *testing.T) {
+	server := httptest.NewServer(echoRouter(WebSocketConfig{}, nil))
+	defer server.Close()
+
+	socket, _ := dialTestSocket(t, server, "/echo", nil)
+
+	socket.writeFrame(true, opText, []byte("hello"))
+	opcode, payload := socket.readFrame()
+	require.Equal(t, byte(opText), opcode)
+	require.Equal(t, "hello", string(payload))
+
+	socket.writeFrame(false, opBinary, []byte("hel"))
+	socket.writeFrame(true, opPing, []byte("ping"))
+	socket.writeFrame(true, opContinuation, []byte("lo"))
+
+	opcode, payload = socket.readFrame()
+	require.Equal(t, byte(opPong), opcode)
+	require.Equal(t, "ping", string(payload))
+
+	opcode, payload = socket.readFrame()
+	require.Equal(t, byte(opBinary), opcode)
+	require.Equal(t, "hello", string(payload))
+
+	large := strings.Repeat("a", 70000)
+	socket.writeFrame(true, opText, []byte(large))
+	_, payload = socket.readFrame()
+	require.Equal(t, large, string(payload))
+}
+
+func TestWebSocket_ClientClose(t *testing.T) {
+	closed := make(chan error, 1)
+	server := httptest.NewServer(echoRouter(WebSocketConfig{}, closed))
+	defer server.Close()
+
+	socket, _ := dialTestSocket(t, server, "/echo", nil)
+	socket.writeFrame(true, opClose, closePayload(CloseGoingAway, "bye"))
+	socket.expectClose(CloseGoingAway)
+
+	var closeErr *CloseError
+	require.True(t, errors.As(<-closed, &closeErr))
+	require.Equal(t, CloseGoingAway, closeErr.Code)
+	require.Equal(t, "bye", closeErr.Reason)
+}
+
+func TestWebSocket_ProtocolErrors(t *testing.T) {
+	testCases := map[string]struct {
+		write func(*testSocket)
+		code  CloseCode
+	}{
+		"message too big": {
+			write: func(s *testSocket) { s.writeFrame(true, opText, []byte("too long")) },
+			code:  CloseMessageTooBig,
+		},
+		"fragmented message too big": {
+			write: func(s *testSocket) {
+				s.writeFrame(false, opText, []byte("four"))
+				s.writeFrame(true, opContinuation, []byte("four"))
+			},
+			code: CloseMessageTooBig,
+		},
+		"invalid utf-8": {
+			write: func(s *testSocket) { s.writeFrame(true, opText, []byte{0xff}) },
+			code:  CloseInvalidPayload,
+		},
+		"unexpected continuation": {
+			write: func(s *testSocket) { s.writeFrame(true, opContinuation, []byte("a")) },
+			code:  CloseProtocolError,
+		},
+		"fragmented control frame": {
+			write: func(s *testSocket) { s.writeFrame(false, opPing, nil) },
+			code:  CloseProtocolError,
+		},
+		"invalid close code": {
+			write: func(s *testSocket) { s.writeFrame(true, opClose, closePayload(1004, "")) },
+			code:  CloseProtocolError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			closed := make(chan error, 1)
+			server := httptest.NewServer(echoRouter(WebSocketConfig{MaxMessageSize: 5}, closed))
+			defer server.Close()
+
+			socket, _ := dialTestSocket(t, server, "/echo", nil)
+			tc.write(socket)
+			socket.expectClose(tc.code)
+
+			var closeErr *CloseError
+			require.True(t, errors.As(<-closed, &closeErr))
+			require.Equal(t, tc.code, closeErr.Code)
+		})
+	}
+}
+
+func TestWebSocket_BeforeFuncsAndRequestData(t *testing.T) {
+	type userData struct{ user string }
+
+	router := New(func(r *RootRequest) userData {
+		return userData{user: r.Request().Header.Get("X-User")}
+	})
+	router.Before(func(ctx context.Context, r *Request[userData], next Next) Response {
+		if r.Data.user == "" {
+			return StringResponse(http.StatusUnauthorized, "401 unauthorized")
+		}
+
+		return next(ctx)
+	})
+	router.Get("/socket", func(ctx context.Context, r *Request[userData]) Response {
+		return WebSocket(ctx, func(ctx context.Context, conn *WebSocketConn) error {
+			return conn.WriteText("hello " + r.Data.user)
+		})
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	_, res := dialTestSocket(t, server, "/socket", nil)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	socket, res := dialTestSocket(t, server, "/socket", http.Header{"X-User": {"fox"}})
+	require.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+
+	_, payload := socket.readFrame()
+	require.Equal(t, "hello fox", string(payload))
+	socket.expectClose(CloseNormal)
+}
+
+func TestWebSocket_HandlerErrorClosesWithInternalError(t *testing.T) {
+	router := New(WithNoData)
+	router.Get("/socket", func(ctx context.Context, r *Request[NoData]) Response {
+		return WebSocket(ctx, func(ctx context.Context, conn *WebSocketConn) error {
+			return errors.New("oops")
+		})
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	socket, _ := dialTestSocket(t, server, "/socket", nil)
+	socket.expectClose(CloseInternalError)
+}